@@ -6,15 +6,21 @@ terraform {
   }
 }
 
+# The API key is read from the RIPE_ATLAS_API_KEY environment variable,
+# or configure one of api_key, api_key_file or api_key_command.
 provider "ripe-atlas" {
-  api_key = "a404ba38-497a-47b0-b8f9-5cb6000dc33f"
+  # api_key_command = ["pass", "show", "ripe-atlas"]
+}
+
+data "ripe-atlas_anchors" "za" {
+  country = "ZA"
 }
 
 resource "ripe-atlas_measurement" "test" {
   count       = 0
   description = "MyFirstTest1"
   type        = "ping"
-  target      = "105.235.209.25"
+  target      = data.ripe-atlas_anchors.za.anchors[0].fqdn
 
   probe_set = [
     { 
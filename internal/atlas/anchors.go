@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package atlas
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Anchor is a RIPE Atlas anchor.
+type Anchor struct {
+	ID         int64     `json:"id"`
+	FQDN       string    `json:"fqdn"`
+	ProbeID    int64     `json:"probe"`
+	IPV4       string    `json:"ip_v4"`
+	IPV6       string    `json:"ip_v6"`
+	ASV4       int64     `json:"as_v4"`
+	ASV6       int64     `json:"as_v6"`
+	City       string    `json:"city"`
+	Country    string    `json:"country"`
+	Company    string    `json:"company"`
+	IsIPv4Only bool      `json:"is_ipv4_only"`
+	IsDisabled bool      `json:"is_disabled"`
+	Geometry   *Geometry `json:"geometry"`
+}
+
+// GetAnchor fetches an anchor.
+func (c *Client) GetAnchor(ctx context.Context, id int64) (*Anchor, error) {
+	anchor := &Anchor{}
+	err := c.request(ctx, http.MethodGet, fmt.Sprintf("anchors/%d/", id), nil, anchor)
+	if err != nil {
+		return nil, err
+	}
+	return anchor, nil
+}
+
+// ListAnchors fetches all anchors matching the filters.
+func (c *Client) ListAnchors(ctx context.Context, filters url.Values) ([]Anchor, error) {
+	return list[Anchor](ctx, c, query("anchors/", filters))
+}
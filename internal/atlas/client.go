@@ -0,0 +1,266 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package atlas is a client for the RIPE Atlas REST API (v2).
+package atlas
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultEndpoint is the API base URL used when no endpoint is configured.
+const DefaultEndpoint = "https://atlas.ripe.net/api/v2"
+
+// Keys are the API keys used per operation.
+// RIPE Atlas keys are scoped, Default is used for operations without a dedicated key.
+type Keys struct {
+	Default string
+	Create  string
+	Delete  string
+	Read    string
+	Credits string
+}
+
+// ForRequest returns the key for a request, falling back to the default key.
+func (k Keys) ForRequest(method string, what string) string {
+	var key string
+	switch {
+	case method == http.MethodDelete:
+		key = k.Delete
+	case method != http.MethodGet:
+		key = k.Create
+	case strings.HasPrefix(what, "credits/"):
+		key = k.Credits
+	default:
+		key = k.Read
+	}
+
+	if key == "" {
+		return k.Default
+	}
+	return key
+}
+
+// Retry configures how failed requests are retried.
+type Retry struct {
+	MaxRetries int64
+	MinWait    time.Duration
+	MaxWait    time.Duration
+}
+
+// DefaultRetry is used for the retry settings that are not configured.
+var DefaultRetry = Retry{
+	MaxRetries: 3,
+	MinWait:    1 * time.Second,
+	MaxWait:    30 * time.Second,
+}
+
+// wait returns how long to wait before the given retry (starting at 0):
+// the Retry-After header if the API sent one, an exponential backoff otherwise.
+func (r Retry) wait(retry int64, resp *http.Response) time.Duration {
+	if resp != nil {
+		if after := resp.Header.Get("Retry-After"); after != "" {
+			if seconds, err := strconv.ParseInt(after, 10, 64); err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second
+			}
+			if date, err := http.ParseTime(after); err == nil {
+				return max(time.Until(date), 0)
+			}
+		}
+	}
+
+	wait := r.MinWait
+	for i := int64(0); i < retry && wait < r.MaxWait; i++ {
+		wait *= 2
+	}
+	return min(wait, r.MaxWait)
+}
+
+// isRetryable returns whether a failed request can be retried.
+// Requests changing data may have been applied before a gateway or network failure,
+// so they are only retried when the API clearly rejected them.
+func isRetryable(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		return method == http.MethodGet
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusServiceUnavailable:
+		return method == http.MethodGet || resp.Header.Get("Retry-After") != ""
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return method == http.MethodGet
+	default:
+		return false
+	}
+}
+
+// Client is a RIPE Atlas API client.
+type Client struct {
+	endpoint string
+	keys     Keys
+	retry    Retry
+	http     *http.Client
+}
+
+// New creates a client for the API at endpoint.
+func New(endpoint string, keys Keys, retry Retry) (*Client, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("endpoint should be an http(s) URL, got: %s", endpoint)
+	}
+
+	return &Client{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		keys:     keys,
+		retry:    retry,
+		http:     &http.Client{Timeout: 20 * time.Second},
+	}, nil
+}
+
+// page is a page of a paginated list.
+type page[T any] struct {
+	Next    string `json:"next"`
+	Results []T    `json:"results"`
+}
+
+// list fetches every page of a list.
+// Only the query of the next page URLs is used: behind a proxy they point at the API itself.
+func list[T any](ctx context.Context, c *Client, what string) ([]T, error) {
+	path, _, _ := strings.Cut(what, "?")
+
+	results := []T{}
+	for what != "" {
+		var p page[T]
+		err := c.request(ctx, http.MethodGet, what, nil, &p)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, p.Results...)
+		if p.Next == "" {
+			break
+		}
+
+		next, err := url.Parse(p.Next)
+		if err != nil || next.RawQuery == "" {
+			return nil, fmt.Errorf("unexpected next page: %s", p.Next)
+		}
+		what = path + "?" + next.RawQuery
+	}
+	return results, nil
+}
+
+// query appends the query parameters to a request path.
+func query(what string, params url.Values) string {
+	if len(params) == 0 {
+		return what
+	}
+	return what + "?" + params.Encode()
+}
+
+// request calls the API, retrying on rate limiting and transient failures (see isRetryable).
+// in (if not nil) is sent as JSON body and the response is decoded into out (if not nil).
+func (c *Client) request(ctx context.Context, method string, what string, in interface{}, out interface{}) error {
+	var encoded []byte
+	if in != nil {
+		var err error
+		encoded, err = json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("unable to encode request: %w", err)
+		}
+	}
+
+	for retry := int64(0); ; retry++ {
+		resp, content, err := c.do(ctx, method, what, encoded)
+		if !isRetryable(method, resp, err) || retry >= c.retry.MaxRetries || ctx.Err() != nil {
+			if err != nil {
+				return err
+			}
+			return decodeResponse(resp, content, out)
+		}
+
+		wait := c.retry.wait(retry, resp)
+		fields := map[string]interface{}{
+			"method": method,
+			"path":   what,
+			"retry":  retry + 1,
+			"wait":   wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.Status
+		}
+		tflog.Warn(ctx, "Retrying RIPE Atlas API request", fields)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// do sends a single request and reads its response.
+func (c *Client) do(ctx context.Context, method string, what string, encoded []byte) (*http.Response, []byte, error) {
+	var body io.Reader
+	if encoded != nil {
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", c.endpoint, what), body)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if encoded != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key := c.keys.ForRequest(method, what); key != "" {
+		req.Header.Set("Authorization", "Key "+key)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read response: %w", err)
+	}
+
+	return resp, content, nil
+}
+
+// decodeResponse decodes the response content into out, or the API error.
+func decodeResponse(resp *http.Response, content []byte, out interface{}) error {
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return decodeError(resp, content)
+	}
+
+	if out != nil && len(content) > 0 {
+		if err := json.Unmarshal(content, out); err != nil {
+			return fmt.Errorf("unable to decode response: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,296 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package atlas
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/credits/" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Key TEST" {
+			t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"current_balance": 42}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL+"/api/v2/", Keys{Default: "TEST"}, DefaultRetry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credits, err := client.GetCredits(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credits.CurrentBalance != 42 {
+		t.Errorf("unexpected balance %d", credits.CurrentBalance)
+	}
+}
+
+func TestClientPagination(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("country_code") != "BE" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("page") == "" {
+			_, _ = w.Write([]byte(`{"next": "` + server.URL + `/probes/?country_code=BE&page=2", "results": [{"id": 1}]}`))
+		} else {
+			_, _ = w.Write([]byte(`{"next": null, "results": [{"id": 2, "tags": [{"name": "IPv6 Works", "slug": "system-ipv6-works"}]}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, Keys{}, DefaultRetry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	probes, err := client.ListProbes(context.Background(), url.Values{"country_code": []string{"BE"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(probes) != 2 || probes[1].ID != 2 || probes[1].Tags[0].Slug != "system-ipv6-works" {
+		t.Errorf("unexpected probes %+v", probes)
+	}
+}
+
+func TestClientPaginationProxy(t *testing.T) {
+	// The API behind the proxy answers next pages on its own host
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/proxy/atlas/anchors/" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") == "" {
+			_, _ = w.Write([]byte(`{"next": "https://atlas.ripe.net/api/v2/anchors/?country=BE&page=2", "results": [{"id": 1}]}`))
+		} else {
+			_, _ = w.Write([]byte(`{"next": null, "results": [{"id": 2}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL+"/proxy/atlas/", Keys{}, DefaultRetry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	anchors, err := client.ListAnchors(context.Background(), url.Values{"country": []string{"BE"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anchors) != 2 || anchors[1].ID != 2 {
+		t.Errorf("unexpected anchors %+v", anchors)
+	}
+}
+
+func TestKeysForRequest(t *testing.T) {
+	keys := Keys{Default: "default", Create: "create", Delete: "delete", Credits: "credits"}
+
+	cases := []struct {
+		method   string
+		what     string
+		expected string
+	}{
+		{http.MethodPost, "measurements/", "create"},
+		{http.MethodPatch, "measurements/1/", "create"},
+		{http.MethodPost, "measurements/1/participation-requests/", "create"},
+		{http.MethodDelete, "measurements/1/", "delete"},
+		{http.MethodGet, "credits/", "credits"},
+		{http.MethodGet, "measurements/1/", "default"},
+	}
+
+	for _, c := range cases {
+		if key := keys.ForRequest(c.method, c.what); key != c.expected {
+			t.Errorf("%s %s: expected %s, got %s", c.method, c.what, c.expected, key)
+		}
+	}
+}
+
+func TestClientInvalidEndpoint(t *testing.T) {
+	_, err := New("atlas.ripe.net", Keys{Default: "TEST"}, DefaultRetry)
+	if err == nil {
+		t.Error("expected an error for an endpoint without scheme")
+	}
+}
+
+func TestClientNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": {"status": 404, "code": 104, "detail": "Not found.", "title": "Not Found"}}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, Keys{Default: "TEST"}, DefaultRetry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.GetMeasurement(context.Background(), 1)
+	if !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestClientFieldErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"status": 400, "code": 102, "detail": "There was a problem with your request", "title": "Bad Request",
+			"errors": [{"source": {"pointer": "/definitions/0/target"}, "detail": "This field is required."}]}}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, Keys{}, DefaultRetry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.CreateMeasurement(context.Background(), MeasurementRequest{})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an API error, got %v", err)
+	}
+	if apiErr.Status != http.StatusBadRequest || len(apiErr.Errors) != 1 || apiErr.Errors[0].Source.Pointer != "/definitions/0/target" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	var attempts atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch attempts.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte(`{"id": 1234}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, Keys{}, Retry{MaxRetries: 2, MinWait: time.Millisecond, MaxWait: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	measurement, err := client.GetMeasurement(context.Background(), 1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts.Load() != 3 || measurement.ID != 1234 {
+		t.Errorf("unexpected result after %d attempts: %+v", attempts.Load(), measurement)
+	}
+
+	// Out of retries
+	attempts.Store(0)
+	client.retry.MaxRetries = 0
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	_, err = client.GetMeasurement(context.Background(), 1)
+	if err == nil || attempts.Load() != 1 {
+		t.Errorf("expected an error after a single attempt, got %v after %d attempts", err, attempts.Load())
+	}
+}
+
+func TestClientRetryCreate(t *testing.T) {
+	cases := []struct {
+		name     string
+		handler  http.HandlerFunc
+		attempts int64
+	}{
+		{"rate limited", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}, 3},
+		{"unavailable with Retry-After", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}, 3},
+		{"unavailable", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}, 1},
+		{"bad gateway", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}, 1},
+		{"gateway timeout", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}, 1},
+		{"connection closed", func(w http.ResponseWriter, r *http.Request) {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		}, 1},
+	}
+
+	for _, c := range cases {
+		var attempts atomic.Int64
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			c.handler(w, r)
+		}))
+
+		client, err := New(server.URL, Keys{}, Retry{MaxRetries: 2, MinWait: time.Millisecond, MaxWait: time.Millisecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The measurement may have been created: only retry when the API refused it
+		_, err = client.CreateMeasurement(context.Background(), MeasurementRequest{})
+		if err == nil || attempts.Load() != c.attempts {
+			t.Errorf("%s: expected an error after %d attempts, got %v after %d attempts", c.name, c.attempts, err, attempts.Load())
+		}
+		server.Close()
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	retry := Retry{MaxRetries: 5, MinWait: time.Second, MaxWait: 5 * time.Second}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, wait := range expected {
+		if actual := retry.wait(int64(i), nil); actual != wait {
+			t.Errorf("retry %d: expected %s, got %s", i, wait, actual)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"10"}}}
+	if actual := retry.wait(0, resp); actual != 10*time.Second {
+		t.Errorf("expected Retry-After to be honoured, got %s", actual)
+	}
+}
+
+func TestIsInsufficientCredits(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{&Error{Status: http.StatusPaymentRequired}, true},
+		{&Error{Status: http.StatusBadRequest, Detail: "You do not have enough credit to schedule this measurement."}, true},
+		{&Error{Status: http.StatusBadRequest, Errors: []FieldError{{Detail: "Insufficient credits"}}}, true},
+		{&Error{Status: http.StatusBadRequest, Detail: "Invalid target"}, false},
+		{errors.New("not enough credits"), false},
+	}
+
+	for _, c := range cases {
+		if actual := IsInsufficientCredits(c.err); actual != c.expected {
+			t.Errorf("%v: expected %t, got %t", c.err, c.expected, actual)
+		}
+	}
+}
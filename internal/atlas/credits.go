@@ -0,0 +1,30 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package atlas
+
+import (
+	"context"
+	"net/http"
+)
+
+// Credits is the credit balance of the account.
+type Credits struct {
+	CurrentBalance            int64 `json:"current_balance"`
+	EstimatedDailyIncome      int64 `json:"estimated_daily_income"`
+	EstimatedDailyExpenditure int64 `json:"estimated_daily_expenditure"`
+	EstimatedDailyBalance     int64 `json:"estimated_daily_balance"`
+	EstimatedRunoutSeconds    int64 `json:"estimated_runout_seconds"`
+	PastDayMeasurementResults int64 `json:"past_day_measurement_results"`
+	PastDayCreditsSpent       int64 `json:"past_day_credits_spent"`
+}
+
+// GetCredits fetches the credit balance.
+func (c *Client) GetCredits(ctx context.Context) (*Credits, error) {
+	credits := &Credits{}
+	err := c.request(ctx, http.MethodGet, "credits/", nil, credits)
+	if err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
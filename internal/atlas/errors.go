@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package atlas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error is an error answered by the API.
+type Error struct {
+	Status int          `json:"status"`
+	Code   int          `json:"code"`
+	Title  string       `json:"title"`
+	Detail string       `json:"detail"`
+	Errors []FieldError `json:"errors"`
+}
+
+// FieldError is an error about a specific field of the request.
+type FieldError struct {
+	Source struct {
+		// JSON pointer to the field, e.g. /definitions/0/target
+		Pointer string `json:"pointer"`
+	} `json:"source"`
+	Detail string `json:"detail"`
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%d %s: %s", e.Status, e.Title, e.Detail)
+	for _, fe := range e.Errors {
+		if fe.Source.Pointer != "" {
+			msg += fmt.Sprintf("\n%s: %s", fe.Source.Pointer, fe.Detail)
+		} else {
+			msg += "\n" + fe.Detail
+		}
+	}
+	return msg
+}
+
+// IsNotFound returns whether the API answered that the requested object does not exist.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+// decodeError returns the error of an unsuccessful response.
+func decodeError(resp *http.Response, content []byte) error {
+	var body struct {
+		Error *Error `json:"error"`
+	}
+	if json.Unmarshal(content, &body) == nil && body.Error != nil {
+		if body.Error.Status == 0 {
+			body.Error.Status = resp.StatusCode
+		}
+		return body.Error
+	}
+
+	return &Error{
+		Status: resp.StatusCode,
+		Title:  http.StatusText(resp.StatusCode),
+		Detail: "unexpected response from RIPE Atlas",
+	}
+}
+
+// IsInsufficientCredits returns whether the API refused a request because the account lacks credits.
+func IsInsufficientCredits(err error) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.Status == http.StatusPaymentRequired {
+		return true
+	}
+
+	messages := []string{apiErr.Title, apiErr.Detail}
+	for _, fe := range apiErr.Errors {
+		messages = append(messages, fe.Detail)
+	}
+	for _, message := range messages {
+		message = strings.ToLower(message)
+		if strings.Contains(message, "credit") && (strings.Contains(message, "enough") || strings.Contains(message, "insufficient")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package atlas
+
+import (
+	"context"
+	"net/url"
+)
+
+// Key is an API key of the account.
+type Key struct {
+	UUID      string  `json:"uuid"`
+	Label     string  `json:"label"`
+	Enabled   bool    `json:"enabled"`
+	IsActive  bool    `json:"is_active"`
+	ValidFrom *string `json:"valid_from"`
+	ValidTo   *string `json:"valid_to"`
+	CreatedAt string  `json:"created_at"`
+	Grants    []Grant `json:"grants"`
+}
+
+// Grant is a permission given by a key.
+type Grant struct {
+	Permission string `json:"permission"`
+}
+
+// ListKeys fetches the API keys of the account.
+func (c *Client) ListKeys(ctx context.Context) ([]Key, error) {
+	return list[Key](ctx, c, query("keys/", url.Values{}))
+}
@@ -0,0 +1,183 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package atlas
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MeasurementStatusStopped is the first status ID of a measurement that ended
+// (Stopped, Forced to stop, No suitable probes, Failed, ...).
+const MeasurementStatusStopped = 4
+
+// Definition is a single measurement definition as sent to and returned by the API.
+// Optional fields are pointers so that only configured values are sent.
+type Definition struct {
+	Type           string   `json:"type"`
+	Description    string   `json:"description"`
+	AF             int64    `json:"af"`
+	Target         string   `json:"target,omitempty"`
+	Interval       *int64   `json:"interval,omitempty"`
+	Spread         *int64   `json:"spread,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	IsPublic       *bool    `json:"is_public,omitempty"`
+	ResolveOnProbe *bool    `json:"resolve_on_probe,omitempty"`
+	// Ping, Traceroute & NTP
+	Packets *int64 `json:"packets,omitempty"`
+	// Ping & Traceroute
+	Size *int64 `json:"size,omitempty"`
+	// Traceroute & DNS
+	Protocol *string `json:"protocol,omitempty"`
+	// Traceroute, HTTP & SSL Certificate
+	Port *int64 `json:"port,omitempty"`
+	// Traceroute
+	Paris                 *int64 `json:"paris,omitempty"`
+	FirstHop              *int64 `json:"first_hop,omitempty"`
+	MaxHops               *int64 `json:"max_hops,omitempty"`
+	ResponseTimeout       *int64 `json:"response_timeout,omitempty"`
+	DestinationOptionSize *int64 `json:"destination_option_size,omitempty"`
+	HopByHopOptionSize    *int64 `json:"hop_by_hop_option_size,omitempty"`
+	DontFragment          *bool  `json:"dont_fragment,omitempty"`
+	// DNS
+	QueryClass       *string `json:"query_class,omitempty"`
+	QueryType        *string `json:"query_type,omitempty"`
+	QueryArgument    *string `json:"query_argument,omitempty"`
+	UseProbeResolver *bool   `json:"use_probe_resolver,omitempty"`
+	SetRDBit         *bool   `json:"set_rd_bit,omitempty"`
+	SetDOBit         *bool   `json:"set_do_bit,omitempty"`
+	SetCDBit         *bool   `json:"set_cd_bit,omitempty"`
+	SetNSIDBit       *bool   `json:"set_nsid_bit,omitempty"`
+	UDPPayloadSize   *int64  `json:"udp_payload_size,omitempty"`
+	Retry            *int64  `json:"retry,omitempty"`
+	IncludeQbuf      *bool   `json:"include_qbuf,omitempty"`
+	IncludeAbuf      *bool   `json:"include_abuf,omitempty"`
+	PrependProbeID   *bool   `json:"prepend_probe_id,omitempty"`
+	// HTTP (always plain HTTP, the API has no HTTPS option)
+	Method             *string `json:"method,omitempty"`
+	Path               *string `json:"path,omitempty"`
+	QueryString        *string `json:"query_string,omitempty"`
+	HeaderBytes        *int64  `json:"header_bytes,omitempty"`
+	Version            *string `json:"version,omitempty"`
+	ExtendedTiming     *bool   `json:"extended_timing,omitempty"`
+	MoreExtendedTiming *bool   `json:"more_extended_timing,omitempty"`
+	// NTP
+	Timeout *int64 `json:"timeout,omitempty"`
+	// SSL Certificate
+	Hostname *string `json:"hostname,omitempty"`
+}
+
+// ProbeSet selects probes for a measurement.
+type ProbeSet struct {
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	Requested int64  `json:"requested"`
+	// Comma separated probe tags
+	TagsInclude string `json:"tags_include,omitempty"`
+	TagsExclude string `json:"tags_exclude,omitempty"`
+}
+
+// MeasurementRequest is the body of POST /measurements/.
+type MeasurementRequest struct {
+	Definitions []Definition `json:"definitions"`
+	Probes      []ProbeSet   `json:"probes"`
+	IsOneoff    bool         `json:"is_oneoff"`
+	StartTime   *int64       `json:"start_time,omitempty"`
+	StopTime    *int64       `json:"stop_time,omitempty"`
+}
+
+// MeasurementUpdate is the body of PATCH /measurements/{id}/.
+type MeasurementUpdate struct {
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	IsPublic    *bool    `json:"is_public,omitempty"`
+}
+
+// Measurement is a measurement as returned by the API.
+type Measurement struct {
+	Definition
+	ID               int64  `json:"id"`
+	IsOneoff         bool   `json:"is_oneoff"`
+	CreationTime     int64  `json:"creation_time"`
+	StartTime        *int64 `json:"start_time"`
+	StopTime         *int64 `json:"stop_time"`
+	Status           Status `json:"status"`
+	ProbesRequested  int64  `json:"probes_requested"`
+	ProbesScheduled  int64  `json:"probes_scheduled"`
+	ParticipantCount *int64 `json:"participant_count"`
+	ResultURL        string `json:"result"`
+	// Only returned with the participation_requests optional field
+	ParticipationRequests []ParticipationRequest `json:"participation_requests"`
+	// Only returned with the probes optional field
+	Probes []struct {
+		ID int64 `json:"id"`
+	} `json:"probes"`
+}
+
+// Status is the status of a measurement.
+type Status struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// ParticipationRequest adds probes to or removes probes from a measurement.
+type ParticipationRequest struct {
+	Action    string `json:"action,omitempty"`
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	Requested int64  `json:"requested"`
+	// Comma separated probe tags
+	TagsInclude string `json:"tags_include,omitempty"`
+	TagsExclude string `json:"tags_exclude,omitempty"`
+}
+
+// CreateMeasurement schedules new measurements and returns their IDs.
+func (c *Client) CreateMeasurement(ctx context.Context, request MeasurementRequest) ([]int64, error) {
+	var created struct {
+		Measurements []int64 `json:"measurements"`
+	}
+	err := c.request(ctx, http.MethodPost, "measurements/", request, &created)
+	if err != nil {
+		return nil, err
+	}
+	return created.Measurements, nil
+}
+
+// GetMeasurement fetches a measurement, including the requested optional fields.
+func (c *Client) GetMeasurement(ctx context.Context, id int64, optionalFields ...string) (*Measurement, error) {
+	params := url.Values{}
+	if len(optionalFields) > 0 {
+		params.Set("optional_fields", strings.Join(optionalFields, ","))
+	}
+
+	measurement := &Measurement{}
+	err := c.request(ctx, http.MethodGet, query(fmt.Sprintf("measurements/%d/", id), params), nil, measurement)
+	if err != nil {
+		return nil, err
+	}
+	return measurement, nil
+}
+
+// ListMeasurements fetches all measurements matching the filters.
+func (c *Client) ListMeasurements(ctx context.Context, filters url.Values) ([]Measurement, error) {
+	return list[Measurement](ctx, c, query("measurements/", filters))
+}
+
+// UpdateMeasurement changes the description, tags or visibility of a measurement.
+func (c *Client) UpdateMeasurement(ctx context.Context, id int64, update MeasurementUpdate) error {
+	return c.request(ctx, http.MethodPatch, fmt.Sprintf("measurements/%d/", id), update, nil)
+}
+
+// StopMeasurement stops a measurement.
+func (c *Client) StopMeasurement(ctx context.Context, id int64) error {
+	return c.request(ctx, http.MethodDelete, fmt.Sprintf("measurements/%d/", id), nil, nil)
+}
+
+// RequestParticipation adds probes to or removes probes from a running measurement.
+func (c *Client) RequestParticipation(ctx context.Context, id int64, requests []ParticipationRequest) error {
+	return c.request(ctx, http.MethodPost, fmt.Sprintf("measurements/%d/participation-requests/", id), requests, nil)
+}
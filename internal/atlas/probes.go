@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package atlas
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Probe is a RIPE Atlas probe.
+// Missing addresses, prefixes and ASNs are left empty (0 for ASNs).
+type Probe struct {
+	ID          int64     `json:"id"`
+	Description string    `json:"description"`
+	AddressV4   string    `json:"address_v4"`
+	AddressV6   string    `json:"address_v6"`
+	PrefixV4    string    `json:"prefix_v4"`
+	PrefixV6    string    `json:"prefix_v6"`
+	ASNV4       int64     `json:"asn_v4"`
+	ASNV6       int64     `json:"asn_v6"`
+	CountryCode string    `json:"country_code"`
+	Geometry    *Geometry `json:"geometry"`
+	Status      struct {
+		ID    int64  `json:"id"`
+		Name  string `json:"name"`
+		Since string `json:"since"`
+	} `json:"status"`
+	IsAnchor       bool  `json:"is_anchor"`
+	IsPublic       bool  `json:"is_public"`
+	FirstConnected int64 `json:"first_connected"`
+	LastConnected  int64 `json:"last_connected"`
+	TotalUptime    int64 `json:"total_uptime"`
+	Tags           []Tag `json:"tags"`
+}
+
+// Geometry is a GeoJSON point.
+type Geometry struct {
+	Type string `json:"type"`
+	// Longitude, latitude
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// Tag is a probe tag.
+type Tag struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// GetProbe fetches a probe.
+func (c *Client) GetProbe(ctx context.Context, id int64) (*Probe, error) {
+	probe := &Probe{}
+	err := c.request(ctx, http.MethodGet, fmt.Sprintf("probes/%d/", id), nil, probe)
+	if err != nil {
+		return nil, err
+	}
+	return probe, nil
+}
+
+// ListProbes fetches all probes matching the filters.
+func (c *Client) ListProbes(ctx context.Context, filters url.Values) ([]Probe, error) {
+	return list[Probe](ctx, c, query("probes/", filters))
+}
@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AnchorsDataSource{}
+var _ datasource.DataSourceWithConfigure = &AnchorsDataSource{}
+
+func NewAnchorsDataSource() datasource.DataSource {
+	return &AnchorsDataSource{}
+}
+
+// AnchorsDataSource defines the data source implementation.
+type AnchorsDataSource struct {
+	client *atlas.Client
+}
+
+// AnchorsDataSourceModel describes the data source data model.
+type AnchorsDataSourceModel struct {
+	// Filters
+	Country types.String `tfsdk:"country"`
+	ASV4    types.Int64  `tfsdk:"as_v4"`
+	ASV6    types.Int64  `tfsdk:"as_v6"`
+	// Results
+	Anchors []AnchorModel `tfsdk:"anchors"`
+}
+
+type AnchorModel struct {
+	ID         types.Int64  `tfsdk:"id"`
+	FQDN       types.String `tfsdk:"fqdn"`
+	ProbeID    types.Int64  `tfsdk:"probe_id"`
+	IPV4       types.String `tfsdk:"ip_v4"`
+	IPV6       types.String `tfsdk:"ip_v6"`
+	ASV4       types.Int64  `tfsdk:"as_v4"`
+	ASV6       types.Int64  `tfsdk:"as_v6"`
+	City       types.String `tfsdk:"city"`
+	Country    types.String `tfsdk:"country"`
+	IsIPv4Only types.Bool   `tfsdk:"is_ipv4_only"`
+}
+
+func (d *AnchorsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_anchors"
+}
+
+func (d *AnchorsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "RIPE Atlas Anchors matching all the given filters",
+
+		Attributes: map[string]schema.Attribute{
+			"country": schema.StringAttribute{
+				MarkdownDescription: "ISO 3166-1 alpha-2 country code",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(2, 2),
+				},
+			},
+			"as_v4": schema.Int64Attribute{
+				MarkdownDescription: "ASN announcing the IPv4 address of the anchor",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"as_v6": schema.Int64Attribute{
+				MarkdownDescription: "ASN announcing the IPv6 address of the anchor",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"anchors": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed: true,
+						},
+						"fqdn": schema.StringAttribute{
+							Computed: true,
+						},
+						"probe_id": schema.Int64Attribute{
+							MarkdownDescription: "ID of the probe of the anchor",
+							Computed:            true,
+						},
+						"ip_v4": schema.StringAttribute{
+							Computed: true,
+						},
+						"ip_v6": schema.StringAttribute{
+							Computed: true,
+						},
+						"as_v4": schema.Int64Attribute{
+							Computed: true,
+						},
+						"as_v6": schema.Int64Attribute{
+							Computed: true,
+						},
+						"city": schema.StringAttribute{
+							Computed: true,
+						},
+						"country": schema.StringAttribute{
+							Computed: true,
+						},
+						"is_ipv4_only": schema.BoolAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AnchorsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*atlas.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *atlas.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// filters converts the configured filters into query parameters of /anchors/.
+func (data *AnchorsDataSourceModel) filters() url.Values {
+	filters := url.Values{}
+	filters.Set("page_size", "500")
+	if !data.Country.IsNull() {
+		filters.Set("country", strings.ToUpper(data.Country.ValueString()))
+	}
+	if !data.ASV4.IsNull() {
+		filters.Set("as_v4", strconv.FormatInt(data.ASV4.ValueInt64(), 10))
+	}
+	if !data.ASV6.IsNull() {
+		filters.Set("as_v6", strconv.FormatInt(data.ASV6.ValueInt64(), 10))
+	}
+	return filters
+}
+
+// anchorModel converts an anchor from the API.
+func anchorModel(anchor atlas.Anchor) AnchorModel {
+	return AnchorModel{
+		ID:         types.Int64Value(anchor.ID),
+		FQDN:       types.StringValue(anchor.FQDN),
+		ProbeID:    optionalInt64(anchor.ProbeID),
+		IPV4:       optionalString(anchor.IPV4),
+		IPV6:       optionalString(anchor.IPV6),
+		ASV4:       optionalInt64(anchor.ASV4),
+		ASV6:       optionalInt64(anchor.ASV6),
+		City:       types.StringValue(anchor.City),
+		Country:    types.StringValue(anchor.Country),
+		IsIPv4Only: types.BoolValue(anchor.IsIPv4Only),
+	}
+}
+
+func (d *AnchorsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Read Terraform data into the model
+	var data AnchorsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Fetch data from API
+	filters := data.filters()
+	ctx = tflog.SetField(ctx, "filters", filters.Encode())
+	anchors, err := d.client.ListAnchors(ctx, filters)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get anchors from RIPE Atlas",
+			err.Error(),
+		)
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "count", len(anchors))
+	tflog.Info(ctx, "RIPE Atlas anchors")
+
+	data.Anchors = []AnchorModel{}
+	for _, anchor := range anchors {
+		data.Anchors = append(data.Anchors, anchorModel(anchor))
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAnchorsDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + testAccAnchorsDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ripe-atlas_anchors.test", "anchors.0.country", "NL"),
+					resource.TestCheckResourceAttr("data.ripe-atlas_anchors.test", "anchors.0.as_v4", "3333"),
+					resource.TestCheckResourceAttrSet("data.ripe-atlas_anchors.test", "anchors.0.fqdn"),
+				),
+			},
+		},
+	})
+}
+
+const testAccAnchorsDataSourceConfig = `
+data "ripe-atlas_anchors" "test" {
+	country = "NL"
+	as_v4   = 3333
+}
+`
+
+func TestAnchorsFilters(t *testing.T) {
+	data := AnchorsDataSourceModel{
+		Country: types.StringValue("nl"),
+		ASV4:    types.Int64Null(),
+		ASV6:    types.Int64Value(3333),
+	}
+
+	expected := "as_v6=3333&country=NL&page_size=500"
+	if actual := data.filters().Encode(); actual != expected {
+		t.Errorf("unexpected filters %s", actual)
+	}
+}
+
+func TestAnchorModel(t *testing.T) {
+	m := anchorModel(atlas.Anchor{
+		ID:         1,
+		FQDN:       "nl-ams-as3333.anchors.atlas.ripe.net",
+		ProbeID:    6001,
+		IPV4:       "193.0.0.1",
+		ASV4:       3333,
+		Country:    "NL",
+		IsIPv4Only: true,
+	})
+	if m.ProbeID.ValueInt64() != 6001 || !m.IPV6.IsNull() || !m.ASV6.IsNull() || !m.IsIPv4Only.ValueBool() {
+		t.Errorf("unexpected anchor %+v", m)
+	}
+}
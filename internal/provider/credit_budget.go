@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// resourceData is given to the resources by the provider.
+type resourceData struct {
+	client *atlas.Client
+	budget *creditBudget
+}
+
+// creditBudget checks the planned measurements against the credit limits of the provider.
+// The daily cost increases of all planned measurements are accumulated across the ModifyPlan calls
+// and the credits are fetched only once. Decreases (removed or smaller measurements) are not deducted:
+// each check only sees the measurements planned so far, and deducting them would make the outcome
+// depend on the order in which Terraform plans the resources.
+type creditBudget struct {
+	client        *atlas.Client
+	maxDailySpend *int64
+	minRunoutDays *int64
+	warn          bool
+
+	mu      sync.Mutex
+	planned int64
+
+	creditsOnce sync.Once
+	credits     *atlas.Credits
+	creditsErr  error
+}
+
+// enabled returns whether any limit is configured.
+func (b *creditBudget) enabled() bool {
+	return b != nil && (b.maxDailySpend != nil || b.minRunoutDays != nil)
+}
+
+// getCredits fetches the credits the first time they are needed.
+func (b *creditBudget) getCredits(ctx context.Context) (*atlas.Credits, error) {
+	b.creditsOnce.Do(func() {
+		b.credits, b.creditsErr = b.client.GetCredits(ctx)
+	})
+	return b.credits, b.creditsErr
+}
+
+// add adds an increase of daily cost and returns the total of all planned increases.
+func (b *creditBudget) add(dailyCost int64) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.planned += dailyCost
+	return b.planned
+}
+
+// check adds the change of daily cost of a measurement to the budget when it increases the spend,
+// and reports whether the planned measurements exceed the limits.
+func (b *creditBudget) check(ctx context.Context, diags *diag.Diagnostics, dailyCost int64) {
+	if !b.enabled() || dailyCost <= 0 {
+		return
+	}
+
+	planned := b.add(dailyCost)
+
+	credits, err := b.getCredits(ctx)
+	if err != nil {
+		diags.AddWarning(
+			"Unable to Check RIPE Atlas Credit Budget",
+			fmt.Sprintf("Unable to get credits from RIPE Atlas, got error: %s", err),
+		)
+		return
+	}
+
+	expenditure := credits.EstimatedDailyExpenditure + planned
+	details := []string{}
+	if b.maxDailySpend != nil && expenditure > *b.maxDailySpend {
+		details = append(details, fmt.Sprintf("The estimated daily spend would be %d credits (%d currently, %d planned), more than max_daily_spend (%d).",
+			expenditure, credits.EstimatedDailyExpenditure, planned, *b.maxDailySpend))
+	}
+	if b.minRunoutDays != nil {
+		if days, ok := runoutDays(credits.CurrentBalance, credits.EstimatedDailyIncome-expenditure); ok && days < *b.minRunoutDays {
+			details = append(details, fmt.Sprintf("The balance of %d credits would run out in %d days (daily income %d credits, daily spend %d credits), sooner than min_runout_days (%d).",
+				credits.CurrentBalance, days, credits.EstimatedDailyIncome, expenditure, *b.minRunoutDays))
+		}
+	}
+
+	for _, detail := range details {
+		if b.warn {
+			diags.AddWarning("RIPE Atlas Credit Budget Exceeded", detail)
+		} else {
+			diags.AddError("RIPE Atlas Credit Budget Exceeded", detail)
+		}
+	}
+}
+
+// runoutDays returns in how many days the balance runs out, if it ever does.
+func runoutDays(balance int64, dailyBalance int64) (int64, bool) {
+	if dailyBalance >= 0 {
+		return 0, false
+	}
+	return max(balance, 0) / -dailyBalance, true
+}
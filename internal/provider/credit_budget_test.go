@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func testCreditBudget(t *testing.T, maxDailySpend *int64, minRunoutDays *int64, warn bool) (*creditBudget, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"current_balance": 10000, "estimated_daily_income": 500, "estimated_daily_expenditure": 200}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := atlas.New(server.URL, atlas.Keys{}, atlas.DefaultRetry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &creditBudget{
+		client:        client,
+		maxDailySpend: maxDailySpend,
+		minRunoutDays: minRunoutDays,
+		warn:          warn,
+	}, &requests
+}
+
+func TestCreditBudgetMaxDailySpend(t *testing.T) {
+	maxDailySpend := int64(1000)
+	budget, requests := testCreditBudget(t, &maxDailySpend, nil, false)
+
+	var diags diag.Diagnostics
+	budget.check(context.Background(), &diags, 500)
+	if diags.HasError() {
+		t.Errorf("unexpected diagnostics within budget: %v", diags)
+	}
+
+	// 200 + 500 + 400 > 1000
+	budget.check(context.Background(), &diags, 400)
+	if diags.ErrorsCount() != 1 {
+		t.Errorf("expected the budget to be exceeded: %v", diags)
+	}
+
+	// Removing a measurement is never reported
+	diags = nil
+	budget.check(context.Background(), &diags, -1000)
+	if len(diags) != 0 {
+		t.Errorf("unexpected diagnostics for a removal: %v", diags)
+	}
+
+	if *requests != 1 {
+		t.Errorf("expected the credits to be fetched once, got %d requests", *requests)
+	}
+}
+
+func TestCreditBudgetOrder(t *testing.T) {
+	maxDailySpend := int64(1000)
+
+	// 200 + 900 > 1000, whether the removal is planned before or after
+	for _, changes := range [][]int64{{900, -1000}, {-1000, 900}} {
+		budget, _ := testCreditBudget(t, &maxDailySpend, nil, false)
+
+		var diags diag.Diagnostics
+		for _, change := range changes {
+			budget.check(context.Background(), &diags, change)
+		}
+		if diags.ErrorsCount() != 1 {
+			t.Errorf("%v: expected the budget to be exceeded: %v", changes, diags)
+		}
+	}
+}
+
+func TestCreditBudgetMinRunoutDays(t *testing.T) {
+	minRunoutDays := int64(30)
+	budget, _ := testCreditBudget(t, nil, &minRunoutDays, true)
+
+	// Daily balance 500 - 200 - 600 = -300: 10000 credits last 33 days
+	var diags diag.Diagnostics
+	budget.check(context.Background(), &diags, 600)
+	if len(diags) != 0 {
+		t.Errorf("unexpected diagnostics: %v", diags)
+	}
+
+	// Daily balance -400: 25 days
+	budget.check(context.Background(), &diags, 100)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("expected a single warning: %v", diags)
+	}
+}
+
+func TestCreditBudgetConcurrent(t *testing.T) {
+	maxDailySpend := int64(1000)
+	budget, requests := testCreditBudget(t, &maxDailySpend, nil, false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var diags diag.Diagnostics
+			budget.check(context.Background(), &diags, 10)
+		}()
+	}
+	wg.Wait()
+
+	if budget.planned != 100 || *requests != 1 {
+		t.Errorf("unexpected budget state: %d planned, %d requests", budget.planned, *requests)
+	}
+}
+
+func TestCreditBudgetDisabled(t *testing.T) {
+	var budget *creditBudget
+	var diags diag.Diagnostics
+	budget.check(context.Background(), &diags, 1000000)
+	if len(diags) != 0 {
+		t.Errorf("unexpected diagnostics: %v", diags)
+	}
+}
@@ -6,9 +6,8 @@ package provider
 import (
 	"context"
 	"fmt"
-	//"net/http"
 
-	"github.com/keltia/ripe-atlas" // PR https://github.com/keltia/ripe-atlas/pull/13
+	"terraform-provider-ripe-atlas/internal/atlas"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -96,7 +95,7 @@ func (d *CreditsDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	}
 
 	// Fetch data from API
-	credits, err := d.client.GetCredits()
+	credits, err := d.client.GetCredits(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to get credits from RIPE Atlas",
@@ -109,11 +108,11 @@ func (d *CreditsDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	tflog.Info(ctx, "RIPE Atlas credits")
 
 	data = CreditsDataSourceModel{
-		EstimatedDailyIncome:      types.Int64Value(int64(credits.EstimatedDailyIncome)),
-		EstimatedDailyExpenditure: types.Int64Value(int64(credits.EstimatedDailyExpenditure)),
-		EstimatedDailyBalance:     types.Int64Value(int64(credits.EstimatedDailyBalance)),
-		CurrentBalance:            types.Int64Value(int64(credits.CurrentBalance)),
-		EstimatedRunoutSeconds:    types.Int64Value(int64(credits.EstimatedRunoutSeconds)),
+		EstimatedDailyIncome:      types.Int64Value(credits.EstimatedDailyIncome),
+		EstimatedDailyExpenditure: types.Int64Value(credits.EstimatedDailyExpenditure),
+		EstimatedDailyBalance:     types.Int64Value(credits.EstimatedDailyBalance),
+		CurrentBalance:            types.Int64Value(credits.CurrentBalance),
+		EstimatedRunoutSeconds:    types.Int64Value(credits.EstimatedRunoutSeconds),
 	}
 
 	// Save data into Terraform state
@@ -0,0 +1,290 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The cost of a measurement is estimated with the RIPE Atlas credit rules:
+// https://atlas.ripe.net/docs/getting-started/credits
+// Defaults are the RIPE Atlas defaults, used for the attributes that are not known yet.
+
+// defaultIntervals are the default intervals (in seconds) per measurement type.
+var defaultIntervals = map[string]int64{
+	"ping":       240,
+	"traceroute": 900,
+	"dns":        240,
+	"sslcert":    900,
+	"http":       1800,
+	"ntp":        1800,
+}
+
+const (
+	defaultPackets = 3
+	defaultSize    = 48
+	secondsPerDay  = 86400
+)
+
+// measurementCost is the estimated cost of a measurement in credits.
+type measurementCost struct {
+	// Per result of a single probe
+	PerResult int64
+	// Per day for all probes (total for one-off measurements)
+	PerDay int64
+	Probes int64
+}
+
+// int64OrDefault returns the value, or the default when it is not known.
+func int64OrDefault(value types.Int64, defaultValue int64) int64 {
+	if value.IsNull() || value.IsUnknown() {
+		return defaultValue
+	}
+	return value.ValueInt64()
+}
+
+// estimatedCost estimates the cost of the measurement(s) managed by the resource.
+func (data *MeasurementResourceModel) estimatedCost() measurementCost {
+	packets := int64OrDefault(data.Packets, defaultPackets)
+	size := int64OrDefault(data.Size, defaultSize)
+
+	var perResult int64
+	switch data.Type.ValueString() {
+	case "ping":
+		perResult = packets * (size/1500 + 1)
+	case "traceroute":
+		perResult = 10 * packets * (size/1500 + 1)
+	case "dns":
+		if data.Protocol.ValueString() == "TCP" {
+			perResult = 20
+		} else {
+			perResult = 10
+		}
+	case "ntp":
+		perResult = packets
+	default: // sslcert, http
+		perResult = 10
+	}
+	if data.IsOneoff.ValueBool() {
+		perResult *= 2
+	}
+
+	cost := measurementCost{PerResult: perResult}
+	for _, ps := range data.ProbeSet {
+		cost.Probes += ps.Number.ValueInt64()
+	}
+
+	cost.PerDay = perResult * cost.Probes
+	if !data.IsOneoff.ValueBool() {
+		interval := int64OrDefault(data.Interval, defaultIntervals[data.Type.ValueString()])
+		if interval > 0 {
+			cost.PerDay = cost.PerDay * secondsPerDay / interval
+		}
+	}
+	if data.DualStack.ValueBool() {
+		cost.PerDay *= 2
+	}
+
+	return cost
+}
+
+// addInsufficientCreditsError explains why a measurement could not be created for lack of credits.
+func (r *MeasurementResource) addInsufficientCreditsError(ctx context.Context, diags *diag.Diagnostics, data *MeasurementResourceModel, err error) {
+	cost := data.estimatedCost()
+
+	period := "per day"
+	if data.IsOneoff.ValueBool() {
+		period = "in total"
+	}
+	detail := fmt.Sprintf("Unable to create %s measurement, the account does not have enough credits: %s\n\n", data.Type.ValueString(), err)
+	detail += fmt.Sprintf("Estimated cost: %d credits per result, %d credits %s for %d probes.\n", cost.PerResult, cost.PerDay, period, cost.Probes)
+
+	credits, creditsErr := r.client.GetCredits(ctx)
+	if creditsErr != nil {
+		detail += fmt.Sprintf("Unable to get the credits balance, got error: %s", creditsErr)
+	} else {
+		detail += fmt.Sprintf("Current balance: %d credits, shortfall: %d credits.\n", credits.CurrentBalance, max(cost.PerDay-credits.CurrentBalance, 0))
+		detail += fmt.Sprintf("Estimated daily income: %d credits, daily expenditure: %d credits.", credits.EstimatedDailyIncome, credits.EstimatedDailyExpenditure)
+	}
+
+	diags.AddError("Insufficient RIPE Atlas Credits", detail)
+}
+
+// setEstimatedCost sets the estimated cost attributes.
+func (data *MeasurementResourceModel) setEstimatedCost() {
+	cost := data.estimatedCost()
+	data.EstimatedCostPerResult = types.Int64Value(cost.PerResult)
+	data.EstimatedCostPerDay = types.Int64Value(cost.PerDay)
+}
+
+// dailyCost returns the estimated daily cost in the state or plan, estimating it when missing.
+func (data *MeasurementResourceModel) dailyCost() int64 {
+	if data.EstimatedCostPerDay.IsNull() || data.EstimatedCostPerDay.IsUnknown() {
+		return data.estimatedCost().PerDay
+	}
+	return data.EstimatedCostPerDay.ValueInt64()
+}
+
+// isCostKnown returns whether the configured attributes the cost depends on are known.
+// Attributes that are not configured are defaulted by RIPE Atlas, but attributes configured
+// from values that are not known yet cannot be estimated with the defaults.
+func (config *MeasurementResourceModel) isCostKnown() bool {
+	for _, value := range []attr.Value{config.Type, config.Protocol, config.IsOneoff, config.DualStack, config.Packets, config.Size, config.Interval} {
+		if value.IsUnknown() {
+			return false
+		}
+	}
+	for _, ps := range config.ProbeSet {
+		if ps.Number.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+// isProbeSetKnown returns whether the planned probe sets are fully known.
+func (data *MeasurementResourceModel) isProbeSetKnown() bool {
+	for _, ps := range data.ProbeSet {
+		for _, value := range []attr.Value{ps.Number, ps.Type, ps.Value} {
+			if value.IsUnknown() {
+				return false
+			}
+		}
+		for _, tag := range append(slices.Clone(ps.TagsInclude), ps.TagsExclude...) {
+			if tag.IsUnknown() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ModifyPlan shows the estimated cost of the measurement in the plan,
+// and checks it against the credit budget of the provider.
+func (r *MeasurementResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var state MeasurementResourceModel
+	var priorCost int64
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		priorCost = state.dailyCost()
+	}
+
+	// Destroy
+	if req.Plan.Raw.IsNull() {
+		r.budget.check(ctx, &resp.Diagnostics, -priorCost)
+		return
+	}
+
+	var data MeasurementResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Probes of area and msm sets cannot be removed from an existing measurement
+	if !req.State.Raw.IsNull() && data.isProbeSetKnown() && probeSetsRequireReplace(state.ProbeSet, data.ProbeSet) {
+		resp.RequiresReplace = resp.RequiresReplace.Append(path.Root("probe_set"))
+	}
+	// Dual stack measurements missing one of their measurements are created again
+	if !req.State.Raw.IsNull() && state.isDualStackIncomplete() {
+		if state.MeasurementIDV4.IsNull() {
+			resp.RequiresReplace = resp.RequiresReplace.Append(path.Root("measurement_id_v4"))
+		}
+		if state.MeasurementIDV6.IsNull() {
+			resp.RequiresReplace = resp.RequiresReplace.Append(path.Root("measurement_id_v6"))
+		}
+	}
+
+	var config MeasurementResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.isCostKnown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_result"), types.Int64Unknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_day"), types.Int64Unknown())...)
+		return
+	}
+
+	data.setEstimatedCost()
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_result"), data.EstimatedCostPerResult)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_day"), data.EstimatedCostPerDay)...)
+
+	// Terraform plans the creation of a replaced measurement again without its state:
+	// its cost is only counted then, to not count it twice.
+	if !req.State.Raw.IsNull() && (len(resp.RequiresReplace) > 0 || requiresReplace(ctx, req)) {
+		return
+	}
+
+	r.budget.check(ctx, &resp.Diagnostics, data.dailyCost()-priorCost)
+}
+
+// requiresReplace returns whether an attribute of the plan requires replacing the measurement.
+// The replacements required by the attribute plan modifiers are not given to ModifyPlan,
+// so the plan modifiers are run again.
+func requiresReplace(ctx context.Context, req resource.ModifyPlanRequest) bool {
+	for name, attribute := range req.Plan.Schema.GetAttributes() {
+		attributePath := path.Root(name)
+
+		switch attribute := attribute.(type) {
+		case schema.StringAttribute:
+			var config, plan, state types.String
+			req.Config.GetAttribute(ctx, attributePath, &config)
+			req.Plan.GetAttribute(ctx, attributePath, &plan)
+			req.State.GetAttribute(ctx, attributePath, &state)
+			for _, modifier := range attribute.PlanModifiers {
+				modifierResp := &planmodifier.StringResponse{PlanValue: plan}
+				modifier.PlanModifyString(ctx, planmodifier.StringRequest{
+					Path: attributePath, Config: req.Config, ConfigValue: config, Plan: req.Plan, PlanValue: plan, State: req.State, StateValue: state,
+				}, modifierResp)
+				if modifierResp.RequiresReplace {
+					return true
+				}
+			}
+		case schema.Int64Attribute:
+			var config, plan, state types.Int64
+			req.Config.GetAttribute(ctx, attributePath, &config)
+			req.Plan.GetAttribute(ctx, attributePath, &plan)
+			req.State.GetAttribute(ctx, attributePath, &state)
+			for _, modifier := range attribute.PlanModifiers {
+				modifierResp := &planmodifier.Int64Response{PlanValue: plan}
+				modifier.PlanModifyInt64(ctx, planmodifier.Int64Request{
+					Path: attributePath, Config: req.Config, ConfigValue: config, Plan: req.Plan, PlanValue: plan, State: req.State, StateValue: state,
+				}, modifierResp)
+				if modifierResp.RequiresReplace {
+					return true
+				}
+			}
+		case schema.BoolAttribute:
+			var config, plan, state types.Bool
+			req.Config.GetAttribute(ctx, attributePath, &config)
+			req.Plan.GetAttribute(ctx, attributePath, &plan)
+			req.State.GetAttribute(ctx, attributePath, &state)
+			for _, modifier := range attribute.PlanModifiers {
+				modifierResp := &planmodifier.BoolResponse{PlanValue: plan}
+				modifier.PlanModifyBool(ctx, planmodifier.BoolRequest{
+					Path: attributePath, Config: req.Config, ConfigValue: config, Plan: req.Plan, PlanValue: plan, State: req.State, StateValue: state,
+				}, modifierResp)
+				if modifierResp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
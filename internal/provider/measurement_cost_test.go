@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestEstimatedCost(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     MeasurementResourceModel
+		expected measurementCost
+	}{
+		{
+			name: "ping defaults",
+			data: MeasurementResourceModel{
+				Type:     types.StringValue("ping"),
+				Interval: types.Int64Unknown(),
+				MeasurementOptionsModel: MeasurementOptionsModel{
+					Packets: types.Int64Unknown(),
+				},
+				ProbeSet: []ProbeSetResourceModel{testProbeSet("country", "BE", 2), testProbeSet("country", "NL", 3)},
+			},
+			expected: measurementCost{PerResult: 3, PerDay: 3 * 5 * 360, Probes: 5},
+		},
+		{
+			name: "large traceroute",
+			data: MeasurementResourceModel{
+				Type:     types.StringValue("traceroute"),
+				Interval: types.Int64Value(3600),
+				MeasurementOptionsModel: MeasurementOptionsModel{
+					Packets: types.Int64Value(2),
+					Size:    types.Int64Value(2000),
+				},
+				ProbeSet: []ProbeSetResourceModel{testProbeSet("country", "BE", 1)},
+			},
+			expected: measurementCost{PerResult: 40, PerDay: 40 * 24, Probes: 1},
+		},
+		{
+			name: "dual stack dns over tcp",
+			data: MeasurementResourceModel{
+				Type:      types.StringValue("dns"),
+				DualStack: types.BoolValue(true),
+				MeasurementOptionsModel: MeasurementOptionsModel{
+					Protocol: types.StringValue("TCP"),
+				},
+				ProbeSet: []ProbeSetResourceModel{testProbeSet("country", "BE", 1)},
+			},
+			expected: measurementCost{PerResult: 20, PerDay: 2 * 20 * 360, Probes: 1},
+		},
+		{
+			name: "one-off http",
+			data: MeasurementResourceModel{
+				Type:     types.StringValue("http"),
+				IsOneoff: types.BoolValue(true),
+				ProbeSet: []ProbeSetResourceModel{testProbeSet("country", "BE", 10)},
+			},
+			expected: measurementCost{PerResult: 20, PerDay: 200, Probes: 10},
+		},
+	}
+
+	for _, c := range cases {
+		if actual := c.data.estimatedCost(); actual != c.expected {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestAddInsufficientCreditsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"current_balance": 1000, "estimated_daily_income": 500, "estimated_daily_expenditure": 200}`))
+	}))
+	defer server.Close()
+
+	client, err := atlas.New(server.URL, atlas.Keys{}, atlas.DefaultRetry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &MeasurementResource{client: client}
+
+	data := MeasurementResourceModel{
+		Type:     types.StringValue("ping"),
+		Interval: types.Int64Value(240),
+		ProbeSet: []ProbeSetResourceModel{testProbeSet("country", "BE", 1)},
+	}
+
+	var diags diag.Diagnostics
+	r.addInsufficientCreditsError(context.Background(), &diags, &data, errors.New("not enough credits"))
+	if len(diags) != 1 {
+		t.Fatalf("expected a single diagnostic, got %v", diags)
+	}
+	for _, expected := range []string{"1080 credits per day", "shortfall: 80 credits", "daily income: 500 credits"} {
+		if !strings.Contains(diags[0].Detail(), expected) {
+			t.Errorf("expected %q in %s", expected, diags[0].Detail())
+		}
+	}
+}
+
+func TestModifyPlanUnknownCost(t *testing.T) {
+	ctx := context.Background()
+	r := &MeasurementResource{}
+
+	modifyPlan := func(packets types.Int64) types.Int64 {
+		data := MeasurementResourceModel{
+			Description: types.StringValue("test"),
+			Type:        types.StringValue("ping"),
+			Target:      types.StringValue("example.com"),
+			IsOneoff:    types.BoolValue(false),
+			DualStack:   types.BoolValue(false),
+			ProbeSet:    []ProbeSetResourceModel{testProbeSet("country", "BE", 1)},
+			MeasurementOptionsModel: MeasurementOptionsModel{
+				Packets: packets,
+			},
+		}
+		config := testMeasurementPlan(t, data)
+		// Attributes defaulted by RIPE Atlas are unknown in the plan
+		data.Packets = types.Int64Unknown()
+		plan := testMeasurementPlan(t, data)
+
+		req := resource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: config.Schema, Raw: config.Raw},
+			Plan:   plan,
+			State:  tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Schema.Type().TerraformType(ctx), nil)},
+		}
+		resp := resource.ModifyPlanResponse{Plan: plan}
+		r.ModifyPlan(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected errors %v", resp.Diagnostics)
+		}
+
+		var cost types.Int64
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("estimated_cost_per_day"), &cost)...)
+		return cost
+	}
+
+	if cost := modifyPlan(types.Int64Null()); cost.ValueInt64() != 3*360 {
+		t.Errorf("expected the cost with the default packets, got %s", cost)
+	}
+	if cost := modifyPlan(types.Int64Unknown()); !cost.IsUnknown() {
+		t.Errorf("expected an unknown cost for unknown packets, got %s", cost)
+	}
+}
+
+func TestModifyPlanReplaceBudget(t *testing.T) {
+	ctx := context.Background()
+	maxDailySpend := int64(1000000)
+	budget, _ := testCreditBudget(t, &maxDailySpend, nil, false)
+	r := &MeasurementResource{budget: budget}
+
+	prior := MeasurementResourceModel{
+		ID:          types.Int64Value(1001),
+		Description: types.StringValue("test"),
+		Type:        types.StringValue("ping"),
+		Target:      types.StringValue("example.com"),
+		Interval:    types.Int64Value(240),
+		IsOneoff:    types.BoolValue(false),
+		DualStack:   types.BoolValue(false),
+		ProbeSet:    []ProbeSetResourceModel{testProbeSet("country", "BE", 1)},
+	}
+	prior.setEstimatedCost()
+	state := testMeasurementPlan(t, prior)
+	nullState := tfsdk.State{Schema: state.Schema, Raw: tftypes.NewValue(state.Schema.Type().TerraformType(ctx), nil)}
+
+	modifyPlan := func(planned MeasurementResourceModel, state tfsdk.State) {
+		plan := testMeasurementPlan(t, planned)
+		req := resource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+			Plan:   plan,
+			State:  state,
+		}
+		resp := resource.ModifyPlanResponse{Plan: plan}
+		r.ModifyPlan(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected errors %v", resp.Diagnostics)
+		}
+	}
+
+	// Update in place: only the difference is counted (1080 -> 1296 credits per day)
+	planned := prior
+	planned.ProbeSet = []ProbeSetResourceModel{testProbeSet("country", "BE", 2)}
+	modifyPlan(planned, tfsdk.State{Schema: state.Schema, Raw: state.Raw})
+	if budget.planned != 1080 {
+		t.Errorf("expected 1080 planned credits, got %d", budget.planned)
+	}
+
+	// Replacement (interval requires replace): planned as an update, then as a creation
+	budget.planned = 0
+	planned = prior
+	planned.Interval = types.Int64Value(120)
+	modifyPlan(planned, tfsdk.State{Schema: state.Schema, Raw: state.Raw})
+	planned.ID = types.Int64Unknown()
+	modifyPlan(planned, nullState)
+	if budget.planned != 2160 {
+		t.Errorf("expected the replacement to be counted once (2160 credits), got %d", budget.planned)
+	}
+}
@@ -6,12 +6,21 @@ package provider
 import (
 	"context"
 	"fmt"
-	//"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 
-	"github.com/keltia/ripe-atlas" // PR https://github.com/keltia/ripe-atlas/pull/13
+	"terraform-provider-ripe-atlas/internal/atlas"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -33,6 +42,19 @@ type MeasurementDataSource struct {
 type MeasurementDataSourceModel struct {
 	Measurements []MeasurementsModel `tfsdk:"measurements"`
 	Hidden       types.Bool          `tfsdk:"hidden"`
+	// Filters
+	Type                types.String   `tfsdk:"type"`
+	Status              types.String   `tfsdk:"status"`
+	Target              types.String   `tfsdk:"target"`
+	TargetASN           types.Int64    `tfsdk:"target_asn"`
+	Tags                []types.String `tfsdk:"tags"`
+	DescriptionContains types.String   `tfsdk:"description_contains"`
+	AF                  types.Int64    `tfsdk:"af"`
+	IsOneoff            types.Bool     `tfsdk:"is_oneoff"`
+	StartTimeGte        types.String   `tfsdk:"start_time_gte"`
+	StartTimeLte        types.String   `tfsdk:"start_time_lte"`
+	// Field selection
+	Fields []types.String `tfsdk:"fields"`
 }
 
 type MeasurementsModel struct {
@@ -45,10 +67,25 @@ type MeasurementsModel struct {
 	Packets  types.Int64 `tfsdk:"packets"`
 	Size     types.Int64 `tfsdk:"size"`
 	// Status (not config)
-	Status types.String    `tfsdk:"status"`
-	Probes ProbeCountModel `tfsdk:"probes"`
+	Status types.String     `tfsdk:"status"`
+	Probes *ProbeCountModel `tfsdk:"probes"`
 }
 
+// measurementStatuses are the measurement status IDs by name.
+var measurementStatuses = map[string]string{
+	"Specified":          "0",
+	"Scheduled":          "1",
+	"Ongoing":            "2",
+	"Stopped":            "4",
+	"Forced to stop":     "5",
+	"No suitable probes": "6",
+	"Failed":             "7",
+	"Denied":             "8",
+}
+
+// measurementFields are the fields that can be selected, the ID is always returned.
+var measurementFields = []string{"description", "type", "target", "interval", "packets", "size", "status", "probes_requested", "probes_scheduled"}
+
 type ProbeCountModel struct {
 	Requested types.Int64 `tfsdk:"requested"`
 	Scheduled types.Int64 `tfsdk:"scheduled"`
@@ -67,6 +104,63 @@ func (d *MeasurementDataSource) Schema(ctx context.Context, req datasource.Schem
 			"hidden": schema.BoolAttribute{
 				Optional: true,
 			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Only measurements of this type",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"ping", "dns", "http", "ntp", "sslcert", "traceroute"}...),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only measurements with this status (Specified, Scheduled, Ongoing, Stopped, Forced to stop, No suitable probes, Failed, Denied)",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("Specified", "Scheduled", "Ongoing", "Stopped", "Forced to stop", "No suitable probes", "Failed", "Denied"),
+				},
+			},
+			"target": schema.StringAttribute{
+				MarkdownDescription: "Only measurements of this target",
+				Optional:            true,
+			},
+			"target_asn": schema.Int64Attribute{
+				MarkdownDescription: "Only measurements of a target in this ASN",
+				Optional:            true,
+			},
+			"tags": schema.ListAttribute{
+				MarkdownDescription: "Only measurements having all these tags",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"description_contains": schema.StringAttribute{
+				MarkdownDescription: "Only measurements whose description contains this text",
+				Optional:            true,
+			},
+			"af": schema.Int64Attribute{
+				MarkdownDescription: "Only measurements of this address family (4 or 6)",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.OneOf(4, 6),
+				},
+			},
+			"is_oneoff": schema.BoolAttribute{
+				Optional: true,
+			},
+			"start_time_gte": schema.StringAttribute{
+				MarkdownDescription: "Only measurements starting at or after this time (RFC3339)",
+				Optional:            true,
+			},
+			"start_time_lte": schema.StringAttribute{
+				MarkdownDescription: "Only measurements starting at or before this time (RFC3339)",
+				Optional:            true,
+			},
+			"fields": schema.ListAttribute{
+				MarkdownDescription: "Only return these fields of the measurements (besides id), the others are null: " + strings.Join(measurementFields, ", "),
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(measurementFields...)),
+				},
+			},
 			"measurements": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -142,13 +236,14 @@ func (d *MeasurementDataSource) Read(ctx context.Context, req datasource.ReadReq
 	}
 
 	// Fetch data from API
-	request := make(map[string]string)
-	request["mine"] = "true"
-	if data.Hidden.ValueBool() {
-		request["hidden"] = "true"
+	request, diags := data.filters()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	measurements, err := d.client.GetMeasurements(request)
+	ctx = tflog.SetField(ctx, "filters", request.Encode())
+	measurements, err := d.client.ListMeasurements(ctx, request)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to get measurements from RIPE Atlas",
@@ -157,31 +252,123 @@ func (d *MeasurementDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
+	ctx = tflog.SetField(ctx, "count", len(measurements))
+	tflog.Info(ctx, "RIPE Atlas measurements")
+
+	data.Measurements = []MeasurementsModel{}
 	for _, measurement := range measurements {
-		ctx = tflog.SetField(ctx, "measurement", measurement)
-		tflog.Info(ctx, "RIPE Atlas measurement")
-
-		m := MeasurementsModel{
-			ID:          types.Int64Value(int64(measurement.ID)),
-			Description: types.StringValue(measurement.Description),
-			Type:        types.StringValue(measurement.Type),
-			Target:      types.StringValue(measurement.Target),
-			// Ping specific ?
-			Interval: types.Int64Value(int64(measurement.Interval)),
-			Packets:  types.Int64Value(int64(measurement.Packets)),
-			Size:     types.Int64Value(int64(measurement.Size)),
-			// Status (not config)
-			Status: types.StringValue(measurement.Status.Name),
-			Probes: ProbeCountModel{
-				Requested: types.Int64Value(int64(measurement.ProbesRequested)),
-				Scheduled: types.Int64Value(int64(measurement.ProbesScheduled)),
-			},
-			// Other: af/creation_time/is_oneoff/packet_interval/start_time/stop_time
-			// Other: port/protocol/
-		}
-		data.Measurements = append(data.Measurements, m)
+		data.Measurements = append(data.Measurements, data.measurementsModel(measurement))
 	}
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// filters converts the configured filters into query parameters of /measurements/.
+func (data *MeasurementDataSourceModel) filters() (url.Values, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	request := url.Values{}
+	request.Set("mine", "true")
+	request.Set("page_size", "500")
+	if data.Hidden.ValueBool() {
+		request.Set("hidden", "true")
+	}
+	if !data.Type.IsNull() {
+		request.Set("type", data.Type.ValueString())
+	}
+	if !data.Status.IsNull() {
+		request.Set("status", measurementStatuses[data.Status.ValueString()])
+	}
+	if !data.Target.IsNull() {
+		request.Set("target", data.Target.ValueString())
+	}
+	if !data.TargetASN.IsNull() {
+		request.Set("target_asn", strconv.FormatInt(data.TargetASN.ValueInt64(), 10))
+	}
+	if len(data.Tags) > 0 {
+		request.Set("tags", strings.Join(stringList(data.Tags), ","))
+	}
+	if !data.DescriptionContains.IsNull() {
+		request.Set("description__contains", data.DescriptionContains.ValueString())
+	}
+	if !data.AF.IsNull() {
+		request.Set("af", strconv.FormatInt(data.AF.ValueInt64(), 10))
+	}
+	if !data.IsOneoff.IsNull() {
+		request.Set("is_oneoff", strconv.FormatBool(data.IsOneoff.ValueBool()))
+	}
+	startTimes := []struct {
+		attribute string
+		param     string
+		value     types.String
+	}{
+		{"start_time_gte", "start_time__gte", data.StartTimeGte},
+		{"start_time_lte", "start_time__lte", data.StartTimeLte},
+	}
+	for _, startTime := range startTimes {
+		timestamp, err := parseTime(startTime.value)
+		if err != nil {
+			diags.AddAttributeError(path.Root(startTime.attribute), "Invalid Start Time", err.Error())
+		} else if timestamp != nil {
+			request.Set(startTime.param, strconv.FormatInt(timestamp.Unix(), 10))
+		}
+	}
+	if len(data.Fields) > 0 {
+		request.Set("fields", strings.Join(append([]string{"id"}, stringList(data.Fields)...), ","))
+	}
+
+	return request, diags
+}
+
+// measurementsModel converts a measurement from the API, keeping the fields that were not selected null.
+func (data *MeasurementDataSourceModel) measurementsModel(measurement atlas.Measurement) MeasurementsModel {
+	selected := func(field string) bool {
+		return len(data.Fields) == 0 || slices.Contains(stringList(data.Fields), field)
+	}
+
+	m := MeasurementsModel{
+		ID:          types.Int64Value(measurement.ID),
+		Description: types.StringNull(),
+		Type:        types.StringNull(),
+		Target:      types.StringNull(),
+		Interval:    types.Int64Null(),
+		Packets:     types.Int64Null(),
+		Size:        types.Int64Null(),
+		Status:      types.StringNull(),
+	}
+	if selected("description") {
+		m.Description = types.StringValue(measurement.Description)
+	}
+	if selected("type") {
+		m.Type = types.StringValue(measurement.Type)
+	}
+	if selected("target") {
+		m.Target = optionalString(measurement.Target)
+	}
+	if selected("interval") {
+		m.Interval = types.Int64PointerValue(measurement.Interval)
+	}
+	if selected("packets") {
+		m.Packets = types.Int64PointerValue(measurement.Packets)
+	}
+	if selected("size") {
+		m.Size = types.Int64PointerValue(measurement.Size)
+	}
+	if selected("status") {
+		m.Status = types.StringValue(measurement.Status.Name)
+	}
+	if selected("probes_requested") || selected("probes_scheduled") {
+		m.Probes = &ProbeCountModel{
+			Requested: types.Int64Null(),
+			Scheduled: types.Int64Null(),
+		}
+		if selected("probes_requested") {
+			m.Probes.Requested = types.Int64Value(measurement.ProbesRequested)
+		}
+		if selected("probes_scheduled") {
+			m.Probes.Scheduled = types.Int64Value(measurement.ProbesScheduled)
+		}
+	}
+	return m
+}
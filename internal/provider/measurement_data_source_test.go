@@ -6,6 +6,9 @@ package provider
 import (
 	"testing"
 
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -21,6 +24,14 @@ func TestAccMeasurementDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.ripe-atlas_measurement.mine", "measurements.0.id", "TEST"),
 				),
 			},
+			{
+				Config: providerConfig + testAccMeasurementDataSourceFilterConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ripe-atlas_measurement.filtered", "measurements.0.type", "ping"),
+					resource.TestCheckResourceAttr("data.ripe-atlas_measurement.filtered", "measurements.0.status", "Ongoing"),
+					resource.TestCheckNoResourceAttr("data.ripe-atlas_measurement.filtered", "measurements.0.description"),
+				),
+			},
 		},
 	})
 }
@@ -30,3 +41,66 @@ data "ripe-atlas_measurement" "mine" {
 	#mine = true
 }
 `
+
+const testAccMeasurementDataSourceFilterConfig = `
+data "ripe-atlas_measurement" "filtered" {
+	type   = "ping"
+	status = "Ongoing"
+	fields = ["type", "status"]
+}
+`
+
+func TestMeasurementFilters(t *testing.T) {
+	data := MeasurementDataSourceModel{
+		Hidden:              types.BoolValue(true),
+		Type:                types.StringValue("ping"),
+		Status:              types.StringValue("Stopped"),
+		Target:              types.StringValue("example.com"),
+		TargetASN:           types.Int64Value(3333),
+		Tags:                []types.String{types.StringValue("a"), types.StringValue("b")},
+		DescriptionContains: types.StringValue("test"),
+		AF:                  types.Int64Value(6),
+		IsOneoff:            types.BoolValue(false),
+		StartTimeGte:        types.StringValue("2024-01-01T00:00:00Z"),
+		StartTimeLte:        types.StringNull(),
+		Fields:              []types.String{types.StringValue("type"), types.StringValue("status")},
+	}
+
+	filters, diags := data.filters()
+	if diags.HasError() {
+		t.Fatalf("unexpected errors %v", diags)
+	}
+	expected := "af=6&description__contains=test&fields=id%2Ctype%2Cstatus&hidden=true&is_oneoff=false&mine=true&page_size=500&start_time__gte=1704067200&status=4&tags=a%2Cb&target=example.com&target_asn=3333&type=ping"
+	if actual := filters.Encode(); actual != expected {
+		t.Errorf("unexpected filters %s", actual)
+	}
+
+	data.StartTimeLte = types.StringValue("yesterday")
+	if _, diags := data.filters(); !diags.HasError() {
+		t.Error("expected an error for an invalid start time")
+	}
+}
+
+func TestMeasurementsModelFields(t *testing.T) {
+	interval := int64(240)
+	measurement := atlas.Measurement{ID: 1, ProbesRequested: 10, ProbesScheduled: 9}
+	measurement.Type = "ping"
+	measurement.Description = "test"
+	measurement.Interval = &interval
+	measurement.Status.Name = "Ongoing"
+
+	data := MeasurementDataSourceModel{}
+	m := data.measurementsModel(measurement)
+	if m.Description.ValueString() != "test" || m.Interval.ValueInt64() != 240 || m.Probes == nil || m.Probes.Scheduled.ValueInt64() != 9 {
+		t.Errorf("unexpected measurement %+v", m)
+	}
+
+	data.Fields = []types.String{types.StringValue("status"), types.StringValue("probes_requested")}
+	m = data.measurementsModel(measurement)
+	if !m.Description.IsNull() || !m.Interval.IsNull() || m.Status.ValueString() != "Ongoing" {
+		t.Errorf("unexpected measurement %+v", m)
+	}
+	if m.Probes == nil || m.Probes.Requested.ValueInt64() != 10 || !m.Probes.Scheduled.IsNull() {
+		t.Errorf("unexpected probes %+v", m.Probes)
+	}
+}
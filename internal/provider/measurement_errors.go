@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// The API reports invalid fields of a measurement request with a pointer into the request body,
+// e.g. /definitions/0/target or /probes/1/value (sometimes written definitions[0].target),
+// and invalid fields of an update with a pointer to the field, e.g. /description.
+
+// probeSetAttributes maps the probe fields of a measurement request to the probe_set attributes.
+var probeSetAttributes = map[string]string{
+	"type":         "type",
+	"value":        "value",
+	"requested":    "number",
+	"tags_include": "tags_include",
+	"tags_exclude": "tags_exclude",
+}
+
+// isMeasurementAttribute returns whether a field of the API is a configurable attribute of the resource,
+// the API knows fields (e.g. skip_dns_check) that the resource does not.
+func isMeasurementAttribute(name string) bool {
+	var resp resource.SchemaResponse
+	NewMeasurementResource().Schema(context.Background(), resource.SchemaRequest{}, &resp)
+
+	attribute, ok := resp.Schema.Attributes[name]
+	return ok && (attribute.IsRequired() || attribute.IsOptional())
+}
+
+// measurementErrorPath converts the pointer of a field error into the path of the matching attribute.
+func measurementErrorPath(pointer string) (path.Path, bool) {
+	pointer = strings.NewReplacer("[", "/", "]", "", ".", "/").Replace(pointer)
+	parts := strings.FieldsFunc(pointer, func(r rune) bool { return r == '/' })
+
+	switch {
+	case len(parts) == 1 && isMeasurementAttribute(parts[0]):
+		// Scheduling fields of a creation and the fields of an update
+		return path.Root(parts[0]), true
+	case len(parts) == 3 && parts[0] == "definitions":
+		// Dual stack definitions share the same attributes
+		if _, err := strconv.Atoi(parts[1]); err != nil || !isMeasurementAttribute(parts[2]) {
+			return path.Empty(), false
+		}
+		return path.Root(parts[2]), true
+	case len(parts) == 3 && parts[0] == "probes":
+		index, err := strconv.Atoi(parts[1])
+		attribute, ok := probeSetAttributes[parts[2]]
+		if err != nil || !ok {
+			return path.Empty(), false
+		}
+		return path.Root("probe_set").AtListIndex(index).AtName(attribute), true
+	case len(parts) == 2 && parts[0] == "probes":
+		index, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return path.Empty(), false
+		}
+		return path.Root("probe_set").AtListIndex(index), true
+	default:
+		return path.Empty(), false
+	}
+}
+
+// addMeasurementError adds an API error to the diagnostics,
+// on the matching attributes when the API reported which fields are invalid.
+func addMeasurementError(diags *diag.Diagnostics, summary string, prefix string, err error) {
+	var apiErr *atlas.Error
+	if !errors.As(err, &apiErr) || len(apiErr.Errors) == 0 {
+		diags.AddError(summary, fmt.Sprintf("%s, got error: %s", prefix, err))
+		return
+	}
+
+	unmapped := []string{}
+	for _, fe := range apiErr.Errors {
+		if attributePath, ok := measurementErrorPath(fe.Source.Pointer); ok {
+			diags.AddAttributeError(attributePath, summary, fmt.Sprintf("%s: %s", prefix, fe.Detail))
+		} else if fe.Source.Pointer != "" {
+			unmapped = append(unmapped, fmt.Sprintf("%s: %s", fe.Source.Pointer, fe.Detail))
+		} else {
+			unmapped = append(unmapped, fe.Detail)
+		}
+	}
+
+	if len(unmapped) > 0 {
+		diags.AddError(summary, fmt.Sprintf("%s, got error: %s\n%s", prefix, apiErr.Detail, strings.Join(unmapped, "\n")))
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestMeasurementErrorPath(t *testing.T) {
+	cases := []struct {
+		pointer  string
+		expected path.Path
+		ok       bool
+	}{
+		{"/definitions/0/target", path.Root("target"), true},
+		{"definitions[1].interval", path.Root("interval"), true},
+		{"/probes/1/value", path.Root("probe_set").AtListIndex(1).AtName("value"), true},
+		{"/probes/0/requested", path.Root("probe_set").AtListIndex(0).AtName("number"), true},
+		{"/probes/2", path.Root("probe_set").AtListIndex(2), true},
+		{"/start_time", path.Root("start_time"), true},
+		{"/description", path.Root("description"), true},
+		{"/tags", path.Root("tags"), true},
+		{"/definitions/0/skip_dns_check", path.Empty(), false},
+		{"/definitions/0/packet_interval", path.Empty(), false},
+		{"/definitions/0/estimated_cost_per_day", path.Empty(), false},
+		{"/non_field_errors", path.Empty(), false},
+		{"/probes/0/unknown", path.Empty(), false},
+		{"/definitions/x/target", path.Empty(), false},
+		{"/definitions/0/non_field_errors", path.Empty(), false},
+		{"", path.Empty(), false},
+	}
+
+	for _, c := range cases {
+		actual, ok := measurementErrorPath(c.pointer)
+		if ok != c.ok || !actual.Equal(c.expected) {
+			t.Errorf("%s: expected %s (%t), got %s (%t)", c.pointer, c.expected, c.ok, actual, ok)
+		}
+	}
+}
+
+func testFieldError(pointer string, detail string) atlas.FieldError {
+	fe := atlas.FieldError{Detail: detail}
+	fe.Source.Pointer = pointer
+	return fe
+}
+
+func TestAddMeasurementError(t *testing.T) {
+	var diags diag.Diagnostics
+	addMeasurementError(&diags, "Client Error", "Unable to create ping measurement", &atlas.Error{
+		Status: 400,
+		Detail: "There was a problem with your request",
+		Errors: []atlas.FieldError{
+			testFieldError("/definitions/0/target", "This field is required."),
+			testFieldError("/probes/1/value", "Invalid country code."),
+			testFieldError("/unknown", "Something else."),
+		},
+	})
+
+	if len(diags) != 3 {
+		t.Fatalf("expected 3 diagnostics, got %d: %v", len(diags), diags)
+	}
+	for i, expected := range []path.Path{path.Root("target"), path.Root("probe_set").AtListIndex(1).AtName("value")} {
+		withPath, ok := diags[i].(diag.DiagnosticWithPath)
+		if !ok || !withPath.Path().Equal(expected) {
+			t.Errorf("diagnostic %d: expected path %s, got %v", i, expected, diags[i])
+		}
+	}
+	if _, ok := diags[2].(diag.DiagnosticWithPath); ok {
+		t.Errorf("expected the unknown field on the measurement, got %v", diags[2])
+	}
+
+	// Errors without field details
+	diags = nil
+	addMeasurementError(&diags, "Client Error", "Unable to create ping measurement", errors.New("connection refused"))
+	if len(diags) != 1 || diags[0].Detail() != "Unable to create ping measurement, got error: connection refused" {
+		t.Errorf("unexpected diagnostics %v", diags)
+	}
+}
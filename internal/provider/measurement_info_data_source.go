@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &MeasurementInfoDataSource{}
+var _ datasource.DataSourceWithConfigure = &MeasurementInfoDataSource{}
+
+func NewMeasurementInfoDataSource() datasource.DataSource {
+	return &MeasurementInfoDataSource{}
+}
+
+// MeasurementInfoDataSource defines the data source implementation.
+type MeasurementInfoDataSource struct {
+	client *atlas.Client
+}
+
+// MeasurementInfoDataSourceModel describes the data source data model.
+type MeasurementInfoDataSourceModel struct {
+	ID             types.Int64    `tfsdk:"id"`
+	Description    types.String   `tfsdk:"description"`
+	Type           types.String   `tfsdk:"type"`
+	Target         types.String   `tfsdk:"target"`
+	AF             types.Int64    `tfsdk:"af"`
+	Interval       types.Int64    `tfsdk:"interval"`
+	Spread         types.Int64    `tfsdk:"spread"`
+	IsOneoff       types.Bool     `tfsdk:"is_oneoff"`
+	IsPublic       types.Bool     `tfsdk:"is_public"`
+	ResolveOnProbe types.Bool     `tfsdk:"resolve_on_probe"`
+	Tags           []types.String `tfsdk:"tags"`
+	// Type specific
+	MeasurementOptionsModel
+	// Status
+	StartTime        types.String `tfsdk:"start_time"`
+	StopTime         types.String `tfsdk:"stop_time"`
+	Status           types.String `tfsdk:"status"`
+	ParticipantCount types.Int64  `tfsdk:"participant_count"`
+	ProbesRequested  types.Int64  `tfsdk:"probes_requested"`
+	ProbesScheduled  types.Int64  `tfsdk:"probes_scheduled"`
+	ResultURL        types.String `tfsdk:"result_url"`
+}
+
+func (d *MeasurementInfoDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_measurement_info"
+}
+
+// computedAttribute converts an attribute of the measurement resource into a computed data source attribute.
+func computedAttribute(attribute resourceschema.Attribute) schema.Attribute {
+	switch attribute.(type) {
+	case resourceschema.Int64Attribute:
+		return schema.Int64Attribute{MarkdownDescription: attribute.GetMarkdownDescription(), Computed: true}
+	case resourceschema.BoolAttribute:
+		return schema.BoolAttribute{MarkdownDescription: attribute.GetMarkdownDescription(), Computed: true}
+	default:
+		return schema.StringAttribute{MarkdownDescription: attribute.GetMarkdownDescription(), Computed: true}
+	}
+}
+
+func (d *MeasurementInfoDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			MarkdownDescription: "Measurement ID",
+			Required:            true,
+		},
+		"tags": schema.ListAttribute{
+			ElementType: types.StringType,
+			Computed:    true,
+		},
+		"start_time": schema.StringAttribute{
+			MarkdownDescription: "Start time (RFC3339)",
+			Computed:            true,
+		},
+		"stop_time": schema.StringAttribute{
+			MarkdownDescription: "Stop time (RFC3339)",
+			Computed:            true,
+		},
+		"status": schema.StringAttribute{
+			MarkdownDescription: "Status (Specified, Scheduled, Ongoing, Stopped, ...)",
+			Computed:            true,
+		},
+		"participant_count": schema.Int64Attribute{
+			MarkdownDescription: "Number of probes participating in the measurement",
+			Computed:            true,
+		},
+		"probes_requested": schema.Int64Attribute{
+			Computed: true,
+		},
+		"probes_scheduled": schema.Int64Attribute{
+			Computed: true,
+		},
+		"result_url": schema.StringAttribute{
+			MarkdownDescription: "URL of the results of the measurement",
+			Computed:            true,
+		},
+	}
+
+	// The definition attributes are described like the measurement resource
+	var measurement resource.SchemaResponse
+	(&MeasurementResource{}).Schema(ctx, resource.SchemaRequest{}, &measurement)
+	names := []string{"description", "type", "target", "af", "interval", "spread", "is_oneoff", "is_public", "resolve_on_probe"}
+	for _, attribute := range (&MeasurementResourceModel{}).typeSpecificAttributes() {
+		names = append(names, attribute.name)
+	}
+	for _, name := range names {
+		attributes[name] = computedAttribute(measurement.Schema.Attributes[name])
+	}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Any RIPE Atlas Measurement, including those of other users",
+
+		Attributes: attributes,
+	}
+}
+
+func (d *MeasurementInfoDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*atlas.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *atlas.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// readMeasurement sets the attributes from a measurement.
+func (data *MeasurementInfoDataSourceModel) readMeasurement(measurement *atlas.Measurement) {
+	data.ID = types.Int64Value(measurement.ID)
+	data.Description = types.StringValue(measurement.Description)
+	data.Type = types.StringValue(measurement.Type)
+	data.Target = optionalString(measurement.Target)
+	data.AF = types.Int64Value(measurement.AF)
+	data.Interval = types.Int64PointerValue(measurement.Interval)
+	data.Spread = types.Int64PointerValue(measurement.Spread)
+	data.IsOneoff = types.BoolValue(measurement.IsOneoff)
+	data.IsPublic = types.BoolPointerValue(measurement.IsPublic)
+	data.ResolveOnProbe = types.BoolPointerValue(measurement.ResolveOnProbe)
+	data.Tags = []types.String{}
+	for _, tag := range measurement.Tags {
+		data.Tags = append(data.Tags, types.StringValue(tag))
+	}
+	data.readOptions(measurement)
+
+	data.StartTime = timeValue(types.StringNull(), measurement.StartTime)
+	data.StopTime = timeValue(types.StringNull(), measurement.StopTime)
+	data.Status = types.StringValue(measurement.Status.Name)
+	data.ParticipantCount = types.Int64PointerValue(measurement.ParticipantCount)
+	data.ProbesRequested = types.Int64Value(measurement.ProbesRequested)
+	data.ProbesScheduled = types.Int64Value(measurement.ProbesScheduled)
+	data.ResultURL = optionalString(measurement.ResultURL)
+}
+
+func (d *MeasurementInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Read Terraform data into the model
+	var data MeasurementInfoDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Fetch data from API
+	measurement, err := d.client.GetMeasurement(ctx, data.ID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get measurement from RIPE Atlas",
+			fmt.Sprintf("Unable to get measurement %d, got error: %s", data.ID.ValueInt64(), err),
+		)
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "measurement", measurement)
+	tflog.Info(ctx, "RIPE Atlas measurement")
+
+	data.readMeasurement(measurement)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccMeasurementInfoDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + testAccMeasurementInfoDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ripe-atlas_measurement_info.test", "type", "ping"),
+					resource.TestCheckResourceAttr("data.ripe-atlas_measurement_info.test", "target", "k.root-servers.net"),
+					resource.TestCheckResourceAttrSet("data.ripe-atlas_measurement_info.test", "packets"),
+					resource.TestCheckResourceAttrSet("data.ripe-atlas_measurement_info.test", "result_url"),
+				),
+			},
+		},
+	})
+}
+
+// Built-in measurement pinging k.root-servers.net
+const testAccMeasurementInfoDataSourceConfig = `
+data "ripe-atlas_measurement_info" "test" {
+	id = 1001
+}
+`
+
+func TestMeasurementInfoSchema(t *testing.T) {
+	var resp datasource.SchemaResponse
+	(&MeasurementInfoDataSource{}).Schema(context.Background(), datasource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	for _, name := range []string{"packets", "query_argument", "hostname", "interval"} {
+		if _, ok := resp.Schema.Attributes[name].(schema.Attribute); !ok {
+			t.Errorf("missing attribute %s", name)
+		}
+	}
+	if _, ok := resp.Schema.Attributes["dont_fragment"].(schema.BoolAttribute); !ok {
+		t.Errorf("unexpected type of dont_fragment: %T", resp.Schema.Attributes["dont_fragment"])
+	}
+}
+
+func TestMeasurementInfoReadMeasurement(t *testing.T) {
+	packets := int64(3)
+	start := int64(1704067200)
+	participants := int64(42)
+	measurement := &atlas.Measurement{
+		Definition:       atlas.Definition{Type: "ping", Target: "k.root-servers.net", AF: 4, Packets: &packets},
+		ID:               1001,
+		StartTime:        &start,
+		ParticipantCount: &participants,
+		ResultURL:        "https://atlas.ripe.net/api/v2/measurements/1001/results/",
+	}
+	measurement.Status.Name = "Ongoing"
+
+	var data MeasurementInfoDataSourceModel
+	data.readMeasurement(measurement)
+
+	if data.Packets.ValueInt64() != 3 || !data.Size.IsNull() {
+		t.Errorf("unexpected options %+v", data.MeasurementOptionsModel)
+	}
+	if data.StartTime.ValueString() != "2024-01-01T00:00:00Z" || !data.StopTime.IsNull() {
+		t.Errorf("unexpected times %s - %s", data.StartTime, data.StopTime)
+	}
+	if data.ParticipantCount.ValueInt64() != 42 || data.Status.ValueString() != "Ongoing" {
+		t.Errorf("unexpected status %+v", data)
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Probe sets of an existing measurement are changed through participation requests:
+// probes are added per set, but can only be removed by probe ID.
+
+// removableSetTypes are the probe set types whose probes can be told apart from the other participants.
+var removableSetTypes = []string{"probes", "country", "asn", "prefix"}
+
+// probeSetKey identifies a probe set regardless of its number of probes.
+type probeSetKey struct {
+	Type        string
+	Value       string
+	TagsInclude string
+	TagsExclude string
+}
+
+func (ps ProbeSetResourceModel) key() probeSetKey {
+	return probeSetKey{
+		Type:        ps.Type.ValueString(),
+		Value:       ps.Value.ValueString(),
+		TagsInclude: strings.Join(stringList(ps.TagsInclude), ","),
+		TagsExclude: strings.Join(stringList(ps.TagsExclude), ","),
+	}
+}
+
+// requestKey identifies the probe set of a participation request.
+func requestKey(pr atlas.ParticipationRequest) probeSetKey {
+	return probeSetKey{
+		Type:        pr.Type,
+		Value:       pr.Value,
+		TagsInclude: pr.TagsInclude,
+		TagsExclude: pr.TagsExclude,
+	}
+}
+
+// tagList converts comma separated tags into a list attribute.
+func tagList(tags string) []types.String {
+	var list []types.String
+	for _, tag := range strings.Split(tags, ",") {
+		if tag != "" {
+			list = append(list, types.StringValue(tag))
+		}
+	}
+	return list
+}
+
+// probeSetReduction is a number of probes to remove from a probe set.
+type probeSetReduction struct {
+	key    probeSetKey
+	number int64
+}
+
+// probeSetChanges compares the prior and planned probe sets and returns
+// the participation requests adding probes and the reductions to resolve to probe IDs.
+func probeSetChanges(prior []ProbeSetResourceModel, planned []ProbeSetResourceModel) ([]atlas.ParticipationRequest, []probeSetReduction) {
+	priorNumbers := map[probeSetKey]int64{}
+	for _, ps := range prior {
+		priorNumbers[ps.key()] += ps.Number.ValueInt64()
+	}
+
+	plannedNumbers := map[probeSetKey]int64{}
+	for _, ps := range planned {
+		plannedNumbers[ps.key()] += ps.Number.ValueInt64()
+	}
+
+	additions := []atlas.ParticipationRequest{}
+	reductions := []probeSetReduction{}
+	for _, ps := range planned {
+		key := ps.key()
+		delta := plannedNumbers[key] - priorNumbers[key]
+		if delta > 0 {
+			additions = append(additions, atlas.ParticipationRequest{
+				Action:      "add",
+				Type:        key.Type,
+				Value:       key.Value,
+				Requested:   delta,
+				TagsInclude: key.TagsInclude,
+				TagsExclude: key.TagsExclude,
+			})
+		} else if delta < 0 {
+			reductions = append(reductions, probeSetReduction{key: key, number: -delta})
+		}
+		// Only handle each set once
+		priorNumbers[key] = plannedNumbers[key]
+	}
+
+	for _, ps := range prior {
+		key := ps.key()
+		if _, ok := plannedNumbers[key]; !ok && priorNumbers[key] > 0 {
+			reductions = append(reductions, probeSetReduction{key: key, number: priorNumbers[key]})
+			priorNumbers[key] = 0
+		}
+	}
+
+	return additions, reductions
+}
+
+// probeSetsRequireReplace returns whether changing the prior probe sets into the planned ones
+// removes probes from sets whose probes cannot be told apart (area, msm), which needs a new measurement.
+func probeSetsRequireReplace(prior []ProbeSetResourceModel, planned []ProbeSetResourceModel) bool {
+	_, reductions := probeSetChanges(prior, planned)
+	for _, reduction := range reductions {
+		if !slices.Contains(removableSetTypes, reduction.key.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileProbeSets folds the participation history of a measurement back onto the declared probe sets.
+// Declared sets without any matching request are dropped, so Terraform will add them again.
+// Without declared sets (import), the probe sets are rebuilt from the history.
+func reconcileProbeSets(declared []ProbeSetResourceModel, history []atlas.ParticipationRequest) []ProbeSetResourceModel {
+	requested := map[probeSetKey]int64{}
+	order := []probeSetKey{}
+	for _, pr := range history {
+		if pr.Action != "" && pr.Action != "add" {
+			continue
+		}
+		if _, ok := requested[requestKey(pr)]; !ok {
+			order = append(order, requestKey(pr))
+		}
+		requested[requestKey(pr)] += pr.Requested
+	}
+
+	probeSets := []ProbeSetResourceModel{}
+	if len(declared) == 0 {
+		for _, key := range order {
+			probeSets = append(probeSets, ProbeSetResourceModel{
+				Type:        types.StringValue(key.Type),
+				Value:       types.StringValue(key.Value),
+				Number:      types.Int64Value(requested[key]),
+				TagsInclude: tagList(key.TagsInclude),
+				TagsExclude: tagList(key.TagsExclude),
+			})
+		}
+		return probeSets
+	}
+
+	for _, ps := range declared {
+		available := requested[ps.key()]
+		if available == 0 {
+			continue
+		}
+
+		number := ps.Number.ValueInt64()
+		if available < number {
+			number = available
+		}
+		requested[ps.key()] -= number
+
+		ps.Number = types.Int64Value(number)
+		probeSets = append(probeSets, ps)
+	}
+
+	return probeSets
+}
+
+// probeMatchesSet returns whether a probe could have been selected by a probe set.
+func probeMatchesSet(probe atlas.Probe, key probeSetKey) (bool, error) {
+	tags := map[string]bool{}
+	for _, tag := range probe.Tags {
+		tags[tag.Slug] = true
+	}
+	for _, tag := range strings.Split(key.TagsInclude, ",") {
+		if tag != "" && !tags[tag] {
+			return false, nil
+		}
+	}
+	for _, tag := range strings.Split(key.TagsExclude, ",") {
+		if tag != "" && tags[tag] {
+			return false, nil
+		}
+	}
+
+	switch key.Type {
+	case "probes":
+		for _, id := range strings.Split(key.Value, ",") {
+			if strings.TrimSpace(id) == strconv.FormatInt(probe.ID, 10) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "country":
+		return strings.EqualFold(probe.CountryCode, key.Value), nil
+	case "asn":
+		asn := strings.TrimPrefix(strings.ToUpper(key.Value), "AS")
+		return asn == strconv.FormatInt(probe.ASNV4, 10) || asn == strconv.FormatInt(probe.ASNV6, 10), nil
+	case "prefix":
+		_, prefix, err := net.ParseCIDR(key.Value)
+		if err != nil {
+			return false, err
+		}
+		for _, address := range []string{probe.AddressV4, probe.AddressV6} {
+			if ip := net.ParseIP(address); ip != nil && prefix.Contains(ip) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default: // area, msm
+		return false, fmt.Errorf("probes cannot be removed from a probe set of type %s, the measurement needs to be replaced", key.Type)
+	}
+}
+
+// getParticipants fetches the probes currently participating in a measurement.
+func (r *MeasurementResource) getParticipants(ctx context.Context, id int64) ([]atlas.Probe, error) {
+	participants, err := r.client.GetMeasurement(ctx, id, "probes")
+	if err != nil {
+		return nil, err
+	}
+
+	probes := []atlas.Probe{}
+	for start := 0; start < len(participants.Probes); start += 100 {
+		end := min(start+100, len(participants.Probes))
+
+		ids := []string{}
+		for _, participant := range participants.Probes[start:end] {
+			ids = append(ids, strconv.FormatInt(participant.ID, 10))
+		}
+
+		page, err := r.client.ListProbes(ctx, url.Values{
+			"id__in":    []string{strings.Join(ids, ",")},
+			"page_size": []string{"100"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, page...)
+	}
+
+	return probes, nil
+}
+
+// updateProbes sends the participation requests changing the prior probe sets into the planned ones.
+func (r *MeasurementResource) updateProbes(ctx context.Context, diags *diag.Diagnostics, id int64, prior []ProbeSetResourceModel, planned []ProbeSetResourceModel) {
+	additions, reductions := probeSetChanges(prior, planned)
+
+	requests := []atlas.ParticipationRequest{}
+	if len(reductions) > 0 {
+		participants, err := r.getParticipants(ctx, id)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("probe_set"),
+				"Client Error",
+				fmt.Sprintf("Unable to get the probes of measurement %d, got error: %s", id, err),
+			)
+			return
+		}
+
+		removed := map[int64]bool{}
+		for _, reduction := range reductions {
+			ids := []string{}
+			for _, probe := range participants {
+				if int64(len(ids)) == reduction.number {
+					break
+				}
+
+				match, err := probeMatchesSet(probe, reduction.key)
+				if err != nil {
+					diags.AddAttributeError(
+						path.Root("probe_set"),
+						"Unable To Remove Probes",
+						fmt.Sprintf("Unable to remove probes from measurement %d: %s", id, err),
+					)
+					return
+				}
+				if match && !removed[probe.ID] {
+					removed[probe.ID] = true
+					ids = append(ids, strconv.FormatInt(probe.ID, 10))
+				}
+			}
+
+			if int64(len(ids)) < reduction.number {
+				diags.AddAttributeWarning(
+					path.Root("probe_set"),
+					"Fewer Probes Removed Than Planned",
+					fmt.Sprintf("%d probes of the %s %s probe set should be removed from measurement %d, but only %d of its participants match the set. The other probes most likely left the measurement already.",
+						reduction.number, reduction.key.Type, reduction.key.Value, id, len(ids)),
+				)
+			}
+			if len(ids) == 0 {
+				continue
+			}
+
+			requests = append(requests, atlas.ParticipationRequest{
+				Action:    "remove",
+				Type:      "probes",
+				Value:     strings.Join(ids, ","),
+				Requested: int64(len(ids)),
+			})
+		}
+	}
+	requests = append(requests, additions...)
+
+	if len(requests) == 0 {
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "participation_requests", requests)
+	tflog.Info(ctx, "Updating probes of RIPE Atlas measurement")
+	if err := r.client.RequestParticipation(ctx, id, requests); err != nil {
+		diags.AddAttributeError(
+			path.Root("probe_set"),
+			"Client Error",
+			fmt.Sprintf("Unable to update probes of measurement %d, got error: %s", id, err),
+		)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testProbeSet(setType string, value string, number int64) ProbeSetResourceModel {
+	return ProbeSetResourceModel{
+		Type:   types.StringValue(setType),
+		Value:  types.StringValue(value),
+		Number: types.Int64Value(number),
+	}
+}
+
+func TestProbeSetChanges(t *testing.T) {
+	prior := []ProbeSetResourceModel{
+		testProbeSet("country", "BE", 2),
+		testProbeSet("country", "NL", 3),
+		testProbeSet("asn", "3333", 1),
+	}
+	planned := []ProbeSetResourceModel{
+		testProbeSet("country", "BE", 5),
+		testProbeSet("country", "NL", 1),
+		testProbeSet("country", "SS", 1),
+	}
+
+	additions, reductions := probeSetChanges(prior, planned)
+
+	expectedAdditions := []atlas.ParticipationRequest{
+		{Action: "add", Type: "country", Value: "BE", Requested: 3},
+		{Action: "add", Type: "country", Value: "SS", Requested: 1},
+	}
+	if !reflect.DeepEqual(additions, expectedAdditions) {
+		t.Errorf("unexpected additions: %+v", additions)
+	}
+
+	expectedReductions := []probeSetReduction{
+		{key: probeSetKey{Type: "country", Value: "NL"}, number: 2},
+		{key: probeSetKey{Type: "asn", Value: "3333"}, number: 1},
+	}
+	if !reflect.DeepEqual(reductions, expectedReductions) {
+		t.Errorf("unexpected reductions: %+v", reductions)
+	}
+}
+
+func TestReconcileProbeSets(t *testing.T) {
+	history := []atlas.ParticipationRequest{
+		{Action: "add", Type: "country", Value: "BE", Requested: 2},
+		{Action: "add", Type: "country", Value: "NL", Requested: 1},
+		{Action: "add", Type: "country", Value: "BE", Requested: 3},
+		{Action: "remove", Type: "probes", Value: "1,2", Requested: 2},
+	}
+
+	// Import
+	imported := reconcileProbeSets(nil, history)
+	expected := []ProbeSetResourceModel{
+		testProbeSet("country", "BE", 5),
+		testProbeSet("country", "NL", 1),
+	}
+	if !reflect.DeepEqual(imported, expected) {
+		t.Errorf("unexpected imported probe sets: %+v", imported)
+	}
+
+	// Declared sets are kept when requested, capped by the history and dropped when never requested
+	declared := []ProbeSetResourceModel{
+		testProbeSet("country", "BE", 4),
+		testProbeSet("country", "NL", 2),
+		testProbeSet("country", "SS", 1),
+	}
+	reconciled := reconcileProbeSets(declared, history)
+	expected = []ProbeSetResourceModel{
+		testProbeSet("country", "BE", 4),
+		testProbeSet("country", "NL", 1),
+	}
+	if !reflect.DeepEqual(reconciled, expected) {
+		t.Errorf("unexpected reconciled probe sets: %+v", reconciled)
+	}
+}
+
+func TestProbeMatchesSet(t *testing.T) {
+	probe := atlas.Probe{
+		ID:          1234,
+		CountryCode: "BE",
+		ASNV4:       3333,
+		AddressV4:   "192.0.2.10",
+		Tags:        []atlas.Tag{{Name: "IPv6 Works", Slug: "system-ipv6-works"}},
+	}
+
+	cases := []struct {
+		key      probeSetKey
+		expected bool
+		err      bool
+	}{
+		{probeSetKey{Type: "probes", Value: "1, 1234"}, true, false},
+		{probeSetKey{Type: "probes", Value: "12345"}, false, false},
+		{probeSetKey{Type: "country", Value: "be"}, true, false},
+		{probeSetKey{Type: "country", Value: "NL"}, false, false},
+		{probeSetKey{Type: "asn", Value: "AS3333"}, true, false},
+		{probeSetKey{Type: "prefix", Value: "192.0.2.0/24"}, true, false},
+		{probeSetKey{Type: "prefix", Value: "198.51.100.0/24"}, false, false},
+		{probeSetKey{Type: "area", Value: "WW"}, false, true},
+		{probeSetKey{Type: "country", Value: "BE", TagsInclude: "system-ipv6-works"}, true, false},
+		{probeSetKey{Type: "country", Value: "BE", TagsInclude: "system-ipv6-works,system-ipv4-works"}, false, false},
+		{probeSetKey{Type: "country", Value: "BE", TagsExclude: "system-ipv6-works"}, false, false},
+	}
+
+	for _, c := range cases {
+		match, err := probeMatchesSet(probe, c.key)
+		if (err != nil) != c.err {
+			t.Errorf("%+v: unexpected error %v", c.key, err)
+		}
+		if match != c.expected {
+			t.Errorf("%+v: expected %t, got %t", c.key, c.expected, match)
+		}
+	}
+}
+
+func TestProbeSetsRequireReplace(t *testing.T) {
+	prior := []ProbeSetResourceModel{
+		testProbeSet("area", "WW", 5),
+		testProbeSet("country", "BE", 2),
+	}
+
+	cases := []struct {
+		name     string
+		planned  []ProbeSetResourceModel
+		expected bool
+	}{
+		{"more area probes", []ProbeSetResourceModel{testProbeSet("area", "WW", 10), testProbeSet("country", "BE", 2)}, false},
+		{"fewer country probes", []ProbeSetResourceModel{testProbeSet("area", "WW", 5), testProbeSet("country", "BE", 1)}, false},
+		{"fewer area probes", []ProbeSetResourceModel{testProbeSet("area", "WW", 4), testProbeSet("country", "BE", 2)}, true},
+		{"area removed", []ProbeSetResourceModel{testProbeSet("country", "BE", 2)}, true},
+	}
+	for _, c := range cases {
+		if actual := probeSetsRequireReplace(prior, c.planned); actual != c.expected {
+			t.Errorf("%s: expected %t, got %t", c.name, c.expected, actual)
+		}
+	}
+}
+
+// testParticipationResource returns a resource whose measurement has the given participants,
+// recording the participation requests sent.
+func testParticipationResource(t *testing.T, participants string, requested *[]atlas.ParticipationRequest) *MeasurementResource {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(requested)
+			_, _ = w.Write([]byte(`{}`))
+		case strings.HasPrefix(r.URL.Path, "/measurements/"):
+			_, _ = w.Write([]byte(`{"id": 1001, "probes": [{"id": 1}, {"id": 2}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"results": ` + participants + `}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := atlas.New(server.URL, atlas.Keys{}, atlas.Retry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &MeasurementResource{client: client}
+}
+
+func TestUpdateProbesFewerParticipants(t *testing.T) {
+	var requested []atlas.ParticipationRequest
+	r := testParticipationResource(t, `[{"id": 1, "country_code": "BE"}, {"id": 2, "country_code": "NL"}]`, &requested)
+
+	// 3 probes to remove from BE, only one participates
+	var diags diag.Diagnostics
+	r.updateProbes(context.Background(), &diags, 1001,
+		[]ProbeSetResourceModel{testProbeSet("country", "BE", 4)},
+		[]ProbeSetResourceModel{testProbeSet("country", "BE", 1)},
+	)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("expected a single warning, got %v", diags)
+	}
+
+	expected := []atlas.ParticipationRequest{{Action: "remove", Type: "probes", Value: "1", Requested: 1}}
+	if !reflect.DeepEqual(requested, expected) {
+		t.Errorf("unexpected participation requests %+v", requested)
+	}
+}
+
+func TestUpdateProbesTagsOnly(t *testing.T) {
+	var requested []atlas.ParticipationRequest
+	r := testParticipationResource(t, `[{"id": 1, "country_code": "BE"}, {"id": 2, "country_code": "BE", "tags": [{"slug": "system-ipv6-works"}]}]`, &requested)
+
+	prior := testProbeSet("country", "BE", 2)
+	planned := testProbeSet("country", "BE", 2)
+	planned.TagsInclude = []types.String{types.StringValue("system-ipv6-works")}
+
+	var diags diag.Diagnostics
+	r.updateProbes(context.Background(), &diags, 1001, []ProbeSetResourceModel{prior}, []ProbeSetResourceModel{planned})
+	if diags.HasError() {
+		t.Fatalf("unexpected errors %v", diags)
+	}
+
+	// The probes selected with the old tags are replaced by probes selected with the new tags
+	expected := []atlas.ParticipationRequest{
+		{Action: "remove", Type: "probes", Value: "1,2", Requested: 2},
+		{Action: "add", Type: "country", Value: "BE", Requested: 2, TagsInclude: "system-ipv6-works"},
+	}
+	if !reflect.DeepEqual(requested, expected) {
+		t.Errorf("unexpected participation requests %+v", requested)
+	}
+
+	// Unchanged probe sets send nothing
+	requested = nil
+	r.updateProbes(context.Background(), &diags, 1001, []ProbeSetResourceModel{planned}, []ProbeSetResourceModel{planned})
+	if diags.HasError() || requested != nil {
+		t.Errorf("unexpected participation requests %+v, diagnostics %v", requested, diags)
+	}
+}
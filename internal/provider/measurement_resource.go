@@ -6,21 +6,26 @@ package provider
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
-	//"net/http"
 
-	"github.com/keltia/ripe-atlas" // PR https://github.com/keltia/ripe-atlas/pull/13
+	"terraform-provider-ripe-atlas/internal/atlas"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -32,6 +37,8 @@ import (
 var _ resource.Resource = &MeasurementResource{}
 var _ resource.ResourceWithImportState = &MeasurementResource{}
 var _ resource.ResourceWithConfigure = &MeasurementResource{}
+var _ resource.ResourceWithValidateConfig = &MeasurementResource{}
+var _ resource.ResourceWithModifyPlan = &MeasurementResource{}
 
 func NewMeasurementResource() resource.Resource {
 	return &MeasurementResource{}
@@ -40,30 +47,95 @@ func NewMeasurementResource() resource.Resource {
 // ExampleResource defines the resource implementation.
 type MeasurementResource struct {
 	client *atlas.Client
+	budget *creditBudget
 }
 
 // ExampleResourceModel describes the resource data model.
 type MeasurementResourceModel struct {
 	ID          types.Int64  `tfsdk:"id"`
 	Description types.String `tfsdk:"description"`
-	Type        types.String `tfsdk:"type"` // TODO: VALIDATE ENUM ?
+	Type        types.String `tfsdk:"type"`
 	Target      types.String `tfsdk:"target"`
-	// Ping specific ?
-	Interval types.Int64 `tfsdk:"interval"`
-	Packets  types.Int64 `tfsdk:"packets"`
-	Size     types.Int64 `tfsdk:"size"`
+	Interval    types.Int64  `tfsdk:"interval"`
+	// Scheduling
+	IsOneoff  types.Bool     `tfsdk:"is_oneoff"`
+	StartTime types.String   `tfsdk:"start_time"`
+	StopTime  types.String   `tfsdk:"stop_time"`
+	Spread    types.Int64    `tfsdk:"spread"`
+	Tags      []types.String `tfsdk:"tags"`
+	IsPublic  types.Bool     `tfsdk:"is_public"`
+	// Address Family
+	AF              types.Int64 `tfsdk:"af"`
+	ResolveOnProbe  types.Bool  `tfsdk:"resolve_on_probe"`
+	DualStack       types.Bool  `tfsdk:"dual_stack"`
+	MeasurementIDV4 types.Int64 `tfsdk:"measurement_id_v4"`
+	MeasurementIDV6 types.Int64 `tfsdk:"measurement_id_v6"`
+	// Type specific
+	MeasurementOptionsModel
 	// Probes (on Create)
 	ProbeSet []ProbeSetResourceModel `tfsdk:"probe_set"`
+	// Credits
+	EstimatedCostPerResult types.Int64 `tfsdk:"estimated_cost_per_result"`
+	EstimatedCostPerDay    types.Int64 `tfsdk:"estimated_cost_per_day"`
 	// Terraform Internal
-	LastUpdated types.String `tfsdk:"last_updated"`
+	RemoveWhenStopped types.Bool     `tfsdk:"remove_when_stopped"`
+	LastUpdated       types.String   `tfsdk:"last_updated"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+// MeasurementOptionsModel are the type-specific attributes of a measurement.
+type MeasurementOptionsModel struct {
+	// Ping, Traceroute & NTP
+	Packets types.Int64 `tfsdk:"packets"`
+	// Ping & Traceroute
+	Size types.Int64 `tfsdk:"size"`
+	// Traceroute & DNS
+	Protocol types.String `tfsdk:"protocol"`
+	// Traceroute, HTTP & SSL Certificate
+	Port types.Int64 `tfsdk:"port"`
+	// Traceroute
+	Paris                 types.Int64 `tfsdk:"paris"`
+	FirstHop              types.Int64 `tfsdk:"first_hop"`
+	MaxHops               types.Int64 `tfsdk:"max_hops"`
+	ResponseTimeout       types.Int64 `tfsdk:"response_timeout"`
+	DestinationOptionSize types.Int64 `tfsdk:"destination_option_size"`
+	HopByHopOptionSize    types.Int64 `tfsdk:"hop_by_hop_option_size"`
+	DontFragment          types.Bool  `tfsdk:"dont_fragment"`
+	// DNS
+	QueryClass       types.String `tfsdk:"query_class"`
+	QueryType        types.String `tfsdk:"query_type"`
+	QueryArgument    types.String `tfsdk:"query_argument"`
+	UseProbeResolver types.Bool   `tfsdk:"use_probe_resolver"`
+	SetRDBit         types.Bool   `tfsdk:"set_rd_bit"`
+	SetDOBit         types.Bool   `tfsdk:"set_do_bit"`
+	SetCDBit         types.Bool   `tfsdk:"set_cd_bit"`
+	SetNSIDBit       types.Bool   `tfsdk:"set_nsid_bit"`
+	UDPPayloadSize   types.Int64  `tfsdk:"udp_payload_size"`
+	Retry            types.Int64  `tfsdk:"retry"`
+	IncludeQbuf      types.Bool   `tfsdk:"include_qbuf"`
+	IncludeAbuf      types.Bool   `tfsdk:"include_abuf"`
+	PrependProbeID   types.Bool   `tfsdk:"prepend_probe_id"`
+	// HTTP
+	Method             types.String `tfsdk:"method"`
+	Path               types.String `tfsdk:"path"`
+	QueryString        types.String `tfsdk:"query_string"`
+	HeaderBytes        types.Int64  `tfsdk:"header_bytes"`
+	Version            types.String `tfsdk:"version"`
+	ExtendedTiming     types.Bool   `tfsdk:"extended_timing"`
+	MoreExtendedTiming types.Bool   `tfsdk:"more_extended_timing"`
+	// NTP
+	Timeout types.Int64 `tfsdk:"timeout"`
+	// SSL Certificate
+	Hostname types.String `tfsdk:"hostname"`
 }
 
 type ProbeSetResourceModel struct {
 	Number types.Int64  `tfsdk:"number"`
 	Type   types.String `tfsdk:"type"` // area, country, prefix, asn, probes, msm
 	Value  types.String `tfsdk:"value"`
-	//TagsInclude			types.String	`tfsdk:"include"`
-	//TagsExclude			types.String	`tfsdk:"exclude"`
+	// Probe Tags
+	TagsInclude []types.String `tfsdk:"tags_include"`
+	TagsExclude []types.String `tfsdk:"tags_exclude"`
 }
 
 func (r *MeasurementResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -86,7 +158,8 @@ func (r *MeasurementResource) Schema(ctx context.Context, req resource.SchemaReq
 				Required: true,
 			},
 			"type": schema.StringAttribute{
-				Required: true,
+				MarkdownDescription: "Type of the measurement (`ping`, `dns`, `http`, `ntp`, `sslcert` or `traceroute`). HTTP measurements use plain HTTP, RIPE Atlas has no HTTPS option: use `sslcert` to measure TLS endpoints.",
+				Required:            true,
 				Validators: []validator.String{
 					stringvalidator.OneOf([]string{"ping", "dns", "http", "ntp", "sslcert", "traceroute"}...),
 				},
@@ -95,44 +168,476 @@ func (r *MeasurementResource) Schema(ctx context.Context, req resource.SchemaReq
 				},
 			},
 			"target": schema.StringAttribute{
-				Required: true,
+				MarkdownDescription: "Target of the measurement (optional for DNS measurements using the probe resolver)",
+				Optional:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"tags": schema.ListAttribute{
+				MarkdownDescription: "Tags of the measurement",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"is_public": schema.BoolAttribute{
+				MarkdownDescription: "Publish the measurement results (a public measurement cannot be made private again)",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+							resp.RequiresReplace = req.StateValue.ValueBool() && !req.PlanValue.IsUnknown() && !req.PlanValue.ValueBool()
+						},
+						"Public measurements cannot be made private again.",
+						"Public measurements cannot be made private again.",
+					),
+				},
+			},
 			"interval": schema.Int64Attribute{
-				Optional: true,
-				Computed: true,
-				Default:  int64default.StaticInt64(300),
+				MarkdownDescription: "Interval between measurements in seconds (defaults to the RIPE Atlas default of the measurement type, not allowed for one-off measurements)",
+				Optional:            true,
+				Computed:            true,
 				Validators: []validator.Int64{
 					int64validator.Between(30, 3600), // 30 sec - 1 hour ?
 				},
 				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
 					int64planmodifier.RequiresReplace(),
 				},
 			},
+			"is_oneoff": schema.BoolAttribute{
+				MarkdownDescription: "Run the measurement only once",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"start_time": schema.StringAttribute{
+				MarkdownDescription: "Start time of the measurement (RFC3339, defaults to as soon as possible)",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"stop_time": schema.StringAttribute{
+				MarkdownDescription: "Stop time of the measurement (RFC3339, defaults to never)",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"spread": schema.Int64Attribute{
+				MarkdownDescription: "Spread of the probes over the interval in seconds",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"af": schema.Int64Attribute{
+				MarkdownDescription: "Address family of the measurement (`4` or `6`)",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(4),
+				Validators: []validator.Int64{
+					int64validator.OneOf(4, 6),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"resolve_on_probe": schema.BoolAttribute{
+				MarkdownDescription: "Resolve the target name on the probe instead of on the RIPE Atlas servers",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"dual_stack": schema.BoolAttribute{
+				MarkdownDescription: "Create both an IPv4 and an IPv6 measurement (`af` must not be set)",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"measurement_id_v4": schema.Int64Attribute{
+				MarkdownDescription: "ID of the IPv4 measurement",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"measurement_id_v6": schema.Int64Attribute{
+				MarkdownDescription: "ID of the IPv6 measurement",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
 			"packets": schema.Int64Attribute{
 				Optional: true,
 				Computed: true,
-				Default:  int64default.StaticInt64(3),
 				Validators: []validator.Int64{
 					int64validator.Between(1, 10), // max 10 packets ?
 				},
 				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
 					int64planmodifier.RequiresReplace(),
 				},
 			},
 			"size": schema.Int64Attribute{
 				Optional: true,
 				Computed: true,
-				Default:  int64default.StaticInt64(48),
 				Validators: []validator.Int64{
 					int64validator.Between(48, 1500), // 48 - 1500 bytes ?
 				},
 				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"protocol": schema.StringAttribute{
+				MarkdownDescription: "Protocol used by traceroute (`ICMP`, `UDP` or `TCP`) and DNS (`UDP` or `TCP`) measurements",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"ICMP", "UDP", "TCP"}...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"paris": schema.Int64Attribute{
+				MarkdownDescription: "Number of paris traceroute variations (0 disables paris traceroute)",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 64),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"first_hop": schema.Int64Attribute{
+				MarkdownDescription: "TTL of the first hop of traceroute measurements",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 255),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
 					int64planmodifier.RequiresReplace(),
 				},
 			},
+			"max_hops": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of hops of traceroute measurements",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 255),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "Destination port of traceroute (TCP only), HTTP and SSL certificate measurements",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"response_timeout": schema.Int64Attribute{
+				MarkdownDescription: "Response timeout of traceroute measurements in milliseconds",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 60000),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"destination_option_size": schema.Int64Attribute{
+				MarkdownDescription: "Size of the IPv6 destination option header of traceroute measurements",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 1024),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"hop_by_hop_option_size": schema.Int64Attribute{
+				MarkdownDescription: "Size of the IPv6 hop-by-hop option header of traceroute measurements",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 2048),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"dont_fragment": schema.BoolAttribute{
+				MarkdownDescription: "Do not fragment outgoing packets of traceroute measurements",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"query_class": schema.StringAttribute{
+				MarkdownDescription: "Query class of DNS measurements (`IN` or `CHAOS`)",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"IN", "CHAOS"}...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"query_type": schema.StringAttribute{
+				MarkdownDescription: "Query type of DNS measurements (`A`, `AAAA`, `NS`, ...)",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"A", "AAAA", "ANY", "CNAME", "DNSKEY", "DS", "MX", "NS", "NSEC", "PTR", "RRSIG", "SOA", "TXT", "SRV", "NAPTR", "TLSA"}...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"query_argument": schema.StringAttribute{
+				MarkdownDescription: "Name to query in DNS measurements",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"use_probe_resolver": schema.BoolAttribute{
+				MarkdownDescription: "Send DNS queries to the resolver(s) of the probe instead of the target",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"set_rd_bit": schema.BoolAttribute{
+				MarkdownDescription: "Set the Recursion Desired bit on DNS queries",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"set_do_bit": schema.BoolAttribute{
+				MarkdownDescription: "Set the DNSSEC OK bit on DNS queries",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"set_cd_bit": schema.BoolAttribute{
+				MarkdownDescription: "Set the Checking Disabled bit on DNS queries",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"set_nsid_bit": schema.BoolAttribute{
+				MarkdownDescription: "Include an EDNS name server ID request in DNS queries",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"udp_payload_size": schema.Int64Attribute{
+				MarkdownDescription: "EDNS UDP payload size of DNS queries",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(512, 4096),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"retry": schema.Int64Attribute{
+				MarkdownDescription: "Number of times to retry DNS queries",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 10),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"include_qbuf": schema.BoolAttribute{
+				MarkdownDescription: "Include the raw DNS query in the results",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"include_abuf": schema.BoolAttribute{
+				MarkdownDescription: "Include the raw DNS answer in the results",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"prepend_probe_id": schema.BoolAttribute{
+				MarkdownDescription: "Prepend the probe ID (and timestamp) to the query argument of DNS measurements",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"method": schema.StringAttribute{
+				MarkdownDescription: "Request method of HTTP measurements (`GET`, `POST` or `HEAD`)",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"GET", "POST", "HEAD"}...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Path requested by HTTP measurements",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"query_string": schema.StringAttribute{
+				MarkdownDescription: "Query string of the URL requested by HTTP measurements",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"header_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of bytes of the response header included in HTTP results",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 2048),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "HTTP version used by HTTP measurements (`1.0` or `1.1`)",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"1.0", "1.1"}...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"extended_timing": schema.BoolAttribute{
+				MarkdownDescription: "Include DNS resolution, connect and time to first byte timings in HTTP results",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"more_extended_timing": schema.BoolAttribute{
+				MarkdownDescription: "Include extended timings and TLS handshake details in HTTP results",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "Per packet timeout of NTP measurements in milliseconds",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 60000),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"hostname": schema.StringAttribute{
+				MarkdownDescription: "Server name (SNI) sent in the TLS handshake of SSL certificate measurements",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"probe_set": schema.ListNestedAttribute{
 				Required: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -161,17 +666,45 @@ func (r *MeasurementResource) Schema(ctx context.Context, req resource.SchemaReq
 								stringplanmodifier.RequiresReplace(),
 							},*/
 						},
+						"tags_include": schema.ListAttribute{
+							MarkdownDescription: "Only select probes having all of these tags (e.g. `system-ipv6-works`)",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
+						"tags_exclude": schema.ListAttribute{
+							MarkdownDescription: "Do not select probes having any of these tags",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
 					},
 				},
 			},
+			"estimated_cost_per_result": schema.Int64Attribute{
+				MarkdownDescription: "Estimated credits spent per result of a single probe",
+				Computed:            true,
+			},
+			"estimated_cost_per_day": schema.Int64Attribute{
+				MarkdownDescription: "Estimated credits spent per day by all probes (in total for one-off measurements)",
+				Computed:            true,
+			},
+			"remove_when_stopped": schema.BoolAttribute{
+				MarkdownDescription: "Remove the measurement from the state (and create it again) when it was stopped outside of Terraform. One-off measurements and measurements that reached their `stop_time` are never removed.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
 			"last_updated": schema.StringAttribute{
 				Computed: true,
-				// TODO: problem ?
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.UseStateForUnknown(),
-				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
@@ -181,18 +714,103 @@ func (r *MeasurementResource) Configure(ctx context.Context, req resource.Config
 		return
 	}
 
-	client, ok := req.ProviderData.(*atlas.Client)
+	data, ok := req.ProviderData.(*resourceData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *atlas.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.budget = data.budget
+}
+
+func (r *MeasurementResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data MeasurementResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Type.IsNull() || data.Type.IsUnknown() {
+		return
+	}
+
+	// Target can only be omitted when DNS queries go to the probe resolver
+	if data.Target.IsNull() && !(data.Type.ValueString() == "dns" && (data.UseProbeResolver.ValueBool() || data.UseProbeResolver.IsUnknown())) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("target"),
+			"Missing Target",
+			"target is required unless use_probe_resolver is set on a DNS measurement.",
+		)
+	}
+
+	if data.Type.ValueString() == "dns" && data.Protocol.ValueString() == "ICMP" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("protocol"),
+			"Invalid Protocol For Measurement Type",
+			"DNS measurements only support the UDP and TCP protocols.",
+		)
+	}
+
+	if data.DualStack.ValueBool() && !data.AF.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("af"),
+			"Conflicting Address Family",
+			"af cannot be set on dual_stack measurements, both IPv4 and IPv6 measurements are created.",
+		)
+	}
+
+	// Scheduling
+	if data.IsOneoff.ValueBool() {
+		if !data.Interval.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("interval"),
+				"Invalid Interval",
+				"interval cannot be set on one-off measurements.",
+			)
+		}
+		if !data.StopTime.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("stop_time"),
+				"Invalid Stop Time",
+				"stop_time cannot be set on one-off measurements.",
+			)
+		}
+	}
+
+	startTime, startErr := parseTime(data.StartTime)
+	if startErr != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("start_time"), "Invalid Start Time", startErr.Error())
+	}
+	stopTime, stopErr := parseTime(data.StopTime)
+	if stopErr != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("stop_time"), "Invalid Stop Time", stopErr.Error())
+	}
+	if startTime != nil && stopTime != nil && !stopTime.After(*startTime) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("stop_time"),
+			"Invalid Stop Time",
+			"stop_time must be after start_time.",
+		)
+	}
+
+	// Type specific attributes should only be set for their type(s)
+	for _, attribute := range data.typeSpecificAttributes() {
+		if attribute.value.IsNull() || slices.Contains(attribute.types, data.Type.ValueString()) {
+			continue
+		}
+
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attribute.name),
+			"Invalid Attribute For Measurement Type",
+			fmt.Sprintf("%s can only be set for %s measurements, not for %s measurements.", attribute.name, strings.Join(attribute.types, "/"), data.Type.ValueString()),
+		)
+	}
 }
 
 func (r *MeasurementResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -203,56 +821,108 @@ func (r *MeasurementResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	timeout, diags := data.Timeouts.Create(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// Prepare creation request
-	request := r.client.NewMeasurement()
-	request.IsOneoff = false // TODO: PARAM ??
-	//request.Times = infinite
-	//request.StartTime = Now
-	//request.StopTime = Never
+	definition := data.definition()
+	request := atlas.MeasurementRequest{
+		Definitions: []atlas.Definition{definition},
+		IsOneoff:    data.IsOneoff.ValueBool(),
+	}
 
-	request.Probes = nil
-	for _, ps := range data.ProbeSet {
-		request.Probes = append(request.Probes, atlas.NewProbeSet(int(ps.Number.ValueInt64()), ps.Type.ValueString(), ps.Value.ValueString(), ""))
+	if startTime, _ := parseTime(data.StartTime); startTime != nil {
+		timestamp := startTime.Unix()
+		request.StartTime = &timestamp
+	}
+	if stopTime, _ := parseTime(data.StopTime); stopTime != nil {
+		timestamp := stopTime.Unix()
+		request.StopTime = &timestamp
+	}
+
+	// Dual Stack: one definition per address family (IPv4 first)
+	if data.DualStack.ValueBool() {
+		request.Definitions[0].AF = 4
+		definition.AF = 6
+		request.Definitions = append(request.Definitions, definition)
 	}
 
-	// Definitions: []Definition
-	opts := map[string]string{
-		"Type":        data.Type.ValueString(),
-		"Description": data.Description.ValueString(),
-		"AF":          "4",
-		"Target":      data.Target.ValueString(),
-		// Ping Only ?
-		"Packets":  strconv.FormatInt(data.Packets.ValueInt64(), 10),
-		"Interval": strconv.FormatInt(data.Interval.ValueInt64(), 10),
-		"Size":     strconv.FormatInt(data.Size.ValueInt64(), 10),
-		// PacketInterval
+	for _, ps := range data.ProbeSet {
+		request.Probes = append(request.Probes, atlas.ProbeSet{
+			Type:        ps.Type.ValueString(),
+			Value:       ps.Value.ValueString(),
+			Requested:   ps.Number.ValueInt64(),
+			TagsInclude: ps.key().TagsInclude,
+			TagsExclude: ps.key().TagsExclude,
+		})
 	}
-	request.AddDefinition(opts)
 
 	// Call API
 	ctx = tflog.SetField(ctx, "request", request)
 	tflog.Info(ctx, "Creating RIPE Atlas measurement")
-	if data.Type.ValueString() == "ping" {
-		measurements, err := r.client.Ping(request)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create ping measurement, got error: %s", err))
+	measurements, err := r.client.CreateMeasurement(ctx, request)
+	if err != nil {
+		if atlas.IsInsufficientCredits(err) {
+			r.addInsufficientCreditsError(ctx, &resp.Diagnostics, &data, err)
 			return
 		}
+		addMeasurementError(&resp.Diagnostics, "Client Error", fmt.Sprintf("Unable to create %s measurement", data.Type.ValueString()), err)
+		return
+	}
 
-		for _, newId := range measurements.Measurements {
-			data.ID = types.Int64Value(int64(newId))
+	if len(measurements) == 0 {
+		resp.Diagnostics.AddError("No ID Retrieved", "Error occurred while creating object. No ID retrieved!")
+		return
+	}
+
+	data.ID = types.Int64Value(measurements[0])
+	data.MeasurementIDV4 = types.Int64Null()
+	data.MeasurementIDV6 = types.Int64Null()
+	for i, newId := range measurements {
+		if i < len(request.Definitions) && request.Definitions[i].AF == 6 {
+			data.MeasurementIDV6 = types.Int64Value(newId)
+		} else {
+			data.MeasurementIDV4 = types.Int64Value(newId)
 		}
-		//TODO: DNS / HTTP / NTP / SSLCert / Traceroute
-	} else {
-		resp.Diagnostics.AddError("Type Error", fmt.Sprintf("Measurement type %s not supported!", data.Type.ValueString()))
+	}
+
+	// The measurements are running (and spending credits) from now on: save their IDs
+	// so that a failure below taints the resource instead of losing track of them.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), data.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("measurement_id_v4"), data.MeasurementIDV4)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("measurement_id_v6"), data.MeasurementIDV6)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("dual_stack"), data.DualStack)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if data.ID.IsUnknown() {
-		resp.Diagnostics.AddError("No ID Retrieved", "Error occurred while creating object. No ID retrieved!")
+	if len(measurements) != len(request.Definitions) {
+		resp.Diagnostics.AddError(
+			"Unexpected Number Of Measurements",
+			fmt.Sprintf("Expected %d measurement IDs from RIPE Atlas, got: %v", len(request.Definitions), measurements),
+		)
 		return
 	}
 
+	// Fetch the measurement to fill in the values defaulted by RIPE Atlas
+	measurement, err := r.getMeasurement(ctx, data.ID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get measurement from RIPE Atlas",
+			err.Error(),
+		)
+		return
+	}
+	data.readMeasurement(measurement)
+	if data.EstimatedCostPerDay.IsUnknown() {
+		data.setEstimatedCost()
+	}
+
 	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	// Save data into Terraform state
@@ -268,44 +938,92 @@ func (r *MeasurementResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	if data.ID.IsUnknown() { // TODO: IF NOT IMPORTED, ID IS UNKNOWN AND SHOULD CREATE NEW MEASUREMENT !
+	timeout, diags := data.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	tflog.Info(ctx, "Fetching RIPE Atlas measurement")
-	measurement, err := r.client.GetMeasurement(int(data.ID.ValueInt64()), true)
-	tflog.Info(ctx, "RIPE Atlas measurement fetched")
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to get measurement from RIPE Atlas",
-			err.Error(),
-		)
+	if data.ID.IsUnknown() { // TODO: IF NOT IMPORTED, ID IS UNKNOWN AND SHOULD CREATE NEW MEASUREMENT !
 		return
 	}
 
-	ctx = tflog.SetField(ctx, "measurement", measurement)
-	tflog.Info(ctx, "RIPE Atlas measurement found")
+	// Check every measurement of the resource, starting with the main one
+	ids := []int64{data.ID.ValueInt64()}
+	for _, id := range data.measurementIDs() {
+		if id != ids[0] {
+			ids = append(ids, id)
+		}
+	}
 
-	probe_set := []ProbeSetResourceModel{}
-	for _, participation := range measurement.ParticipationRequests {
-		probe_set = append(probe_set, ProbeSetResourceModel{
-			Type:   types.StringValue(participation.Type),
-			Value:  types.StringValue(participation.Value),
-			Number: types.Int64Value(int64(participation.Requested)),
-		})
+	var measurement *atlas.Measurement
+	gone := map[int64]bool{}
+	for _, id := range ids {
+		ctx := tflog.SetField(ctx, "id", id)
+		tflog.Info(ctx, "Fetching RIPE Atlas measurement")
+		fetched, err := r.getMeasurement(ctx, id)
+		if atlas.IsNotFound(err) {
+			tflog.Warn(ctx, "RIPE Atlas measurement not found")
+			gone[id] = true
+			continue
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to get measurement from RIPE Atlas",
+				err.Error(),
+			)
+			return
+		}
+
+		ctx = tflog.SetField(ctx, "measurement", fetched)
+		tflog.Info(ctx, "RIPE Atlas measurement found")
+
+		if data.RemoveWhenStopped.ValueBool() && !fetched.IsOneoff && fetched.Status.ID >= atlas.MeasurementStatusStopped && !data.stoppedAsScheduled(fetched) {
+			ctx = tflog.SetField(ctx, "status", fetched.Status.Name)
+			tflog.Warn(ctx, "RIPE Atlas measurement stopped")
+			gone[id] = true
+			continue
+		}
+
+		if measurement == nil {
+			measurement = fetched
+		}
 	}
 
-	data = MeasurementResourceModel{
-		ID:          types.Int64Value(int64(measurement.ID)),
-		Description: types.StringValue(measurement.Description),
-		Type:        types.StringValue(measurement.Type),
-		Target:      types.StringValue(measurement.Target),
-		// Ping specific ?
-		Interval: types.Int64Value(int64(measurement.Interval)),
-		Packets:  types.Int64Value(int64(measurement.Packets)),
-		Size:     types.Int64Value(int64(measurement.Size)),
-		ProbeSet: probe_set,
-		//            LastUpdated
+	if measurement == nil {
+		tflog.Warn(ctx, "RIPE Atlas measurements gone, removing them from the state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Dual stack measurement with a single measurement left: forget the other one,
+	// the resource is then replaced (see ModifyPlan) and the one left is stopped.
+	if gone[data.MeasurementIDV4.ValueInt64()] {
+		data.MeasurementIDV4 = types.Int64Null()
+	}
+	if gone[data.MeasurementIDV6.ValueInt64()] {
+		data.MeasurementIDV6 = types.Int64Null()
+	}
+
+	data.readMeasurement(measurement)
+	data.ProbeSet = reconcileProbeSets(data.ProbeSet, measurement.ParticipationRequests)
+	data.setEstimatedCost()
+
+	// Imported single stack measurement
+	if data.DualStack.IsNull() {
+		data.DualStack = types.BoolValue(false)
+	}
+	if data.RemoveWhenStopped.IsNull() {
+		data.RemoveWhenStopped = types.BoolValue(false)
+	}
+	if data.MeasurementIDV4.IsNull() && data.MeasurementIDV6.IsNull() {
+		if measurement.AF == 6 {
+			data.MeasurementIDV6 = data.ID
+		} else {
+			data.MeasurementIDV4 = data.ID
+		}
 	}
 
 	// Save updated data into Terraform state
@@ -313,46 +1031,67 @@ func (r *MeasurementResource) Read(ctx context.Context, req resource.ReadRequest
 }
 
 func (r *MeasurementResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Read Terraform plan data into the model
-	var data MeasurementResourceModel
+	// Read Terraform plan and prior state data into the model
+	var data, state MeasurementResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Update Description (IF...?)
-	ctx = tflog.SetField(ctx, "new_description", data.Description)
-	tflog.Info(ctx, "Updating description...")
-
-	// TODO: API - PATCH {description: "MyFirstTest1"}
+	timeout, diags := data.Timeouts.Update(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	// Update Probes (IF...?)
-	ctx = tflog.SetField(ctx, "new_description", data.ProbeSet)
-	tflog.Info(ctx, "Updating probes...")
+	// Update Probes (nothing is sent when the probe sets did not change)
+	for _, id := range data.measurementIDs() {
+		r.updateProbes(ctx, &resp.Diagnostics, id, state.ProbeSet, data.ProbeSet)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
-	// TODO: API - POST ID/participation-requests {type: "country", value: "SS", requested: "1", action: "add"}
+	// Update Description, Tags and Public flag (which can only be turned on)
+	update := atlas.MeasurementUpdate{
+		Description: data.Description.ValueString(),
+		Tags:        stringList(data.Tags),
+	}
+	if data.IsPublic.ValueBool() && !state.IsPublic.ValueBool() {
+		update.IsPublic = boolPointer(data.IsPublic)
+	}
 
-	resp.Diagnostics.AddError(
-		"Update not supported!",
-		"Update is currently not supported!",
-	)
-	return
+	for _, id := range data.measurementIDs() {
+		ctx = tflog.SetField(ctx, "id", id)
+		ctx = tflog.SetField(ctx, "update", update)
+		tflog.Info(ctx, "Updating RIPE Atlas measurement")
+		err := r.client.UpdateMeasurement(ctx, id, update)
+		if err != nil {
+			addMeasurementError(&resp.Diagnostics, "Client Error", fmt.Sprintf("Unable to update measurement %d", id), err)
+			return
+		}
+	}
 
-	// tflog.Info(ctx, "Fetching RIPE Atlas measurement")
-	// _, err := r.client.GetMeasurement(int(data.ID.ValueInt64()))
-	// tflog.Info(ctx, "RIPE Atlas measurement fetched")
-	// if err != nil {
-	//   resp.Diagnostics.AddError(
-	//     "Unable to get measurement from RIPE Atlas",
-	//     err.Error(),
-	//   )
-	//   return
-	// }
+	measurement, err := r.getMeasurement(ctx, data.ID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get measurement from RIPE Atlas",
+			err.Error(),
+		)
+		return
+	}
+	data.readMeasurement(measurement)
+	if data.EstimatedCostPerDay.IsUnknown() {
+		data.setEstimatedCost()
+	}
 
-	//data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	// Save updated data into Terraform state
-	//resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *MeasurementResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -363,30 +1102,338 @@ func (r *MeasurementResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	tflog.Info(ctx, "Deleting RIPE Atlas measurement")
-	err := r.client.DeleteMeasurement(int(data.ID.ValueInt64()))
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to get measurement from RIPE Atlas",
-			err.Error(),
-		)
+	timeout, diags := data.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
-	} else {
-		tflog.Info(ctx, "RIPE Atlas measurement deleted")
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, id := range data.measurementIDs() {
+		ctx = tflog.SetField(ctx, "id", id)
+		tflog.Info(ctx, "Deleting RIPE Atlas measurement")
+		err := r.client.StopMeasurement(ctx, id)
+		if atlas.IsNotFound(err) {
+			tflog.Info(ctx, "RIPE Atlas measurement already deleted")
+			continue
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to get measurement from RIPE Atlas",
+				err.Error(),
+			)
+			return
+		} else {
+			tflog.Info(ctx, "RIPE Atlas measurement deleted")
+		}
 	}
 
 	// TODO: HIDE ON UI ???
 }
 
 func (r *MeasurementResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.ParseInt(req.ID, 10, 64)
-	if err != nil {
+	// Dual Stack measurements are imported as "<IPv4 ID>,<IPv6 ID>"
+	ids := strings.Split(req.ID, ",")
+	if len(ids) > 2 {
 		resp.Diagnostics.AddError(
 			"Error importing item",
-			"Could not import item, unexpected error (ID should be an integer): "+err.Error(),
+			"Could not import item, expected a measurement ID or <IPv4 ID>,<IPv6 ID> for dual stack measurements.",
 		)
 		return
 	}
 
-	resp.State.SetAttribute(ctx, path.Root("id"), id)
+	var parsed []int64
+	for _, value := range ids {
+		id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error importing item",
+				"Could not import item, unexpected error (ID should be an integer): "+err.Error(),
+			)
+			return
+		}
+		parsed = append(parsed, id)
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parsed[0])...)
+	if len(parsed) == 2 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("dual_stack"), true)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("measurement_id_v4"), parsed[0])...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("measurement_id_v6"), parsed[1])...)
+	}
+}
+
+// getMeasurement fetches a measurement including its participation requests.
+func (r *MeasurementResource) getMeasurement(ctx context.Context, id int64) (*atlas.Measurement, error) {
+	return r.client.GetMeasurement(ctx, id, "participation_requests")
+}
+
+// isDualStackIncomplete returns whether one of the measurements of a dual stack resource is gone.
+func (data *MeasurementResourceModel) isDualStackIncomplete() bool {
+	return data.DualStack.ValueBool() && !data.ID.IsNull() && (data.MeasurementIDV4.IsNull() || data.MeasurementIDV6.IsNull())
+}
+
+// measurementIDs returns the IDs of all measurements managed by the resource.
+func (data *MeasurementResourceModel) measurementIDs() []int64 {
+	if !data.DualStack.ValueBool() {
+		return []int64{data.ID.ValueInt64()}
+	}
+
+	ids := []int64{}
+	for _, id := range []types.Int64{data.MeasurementIDV4, data.MeasurementIDV6} {
+		if !id.IsNull() && !id.IsUnknown() {
+			ids = append(ids, id.ValueInt64())
+		}
+	}
+	return ids
+}
+
+// stoppedAsScheduled returns whether a measurement stopped because it reached the stop_time of the resource.
+func (data *MeasurementResourceModel) stoppedAsScheduled(measurement *atlas.Measurement) bool {
+	stopTime, _ := parseTime(data.StopTime)
+	return stopTime != nil && measurement.StopTime != nil && *measurement.StopTime >= stopTime.Unix()
+}
+
+// measurementAttribute is a type specific attribute and the measurement types it applies to.
+type measurementAttribute struct {
+	name  string
+	value attr.Value
+	types []string
+}
+
+func (data *MeasurementResourceModel) typeSpecificAttributes() []measurementAttribute {
+	return []measurementAttribute{
+		{"packets", data.Packets, []string{"ping", "traceroute", "ntp"}},
+		{"size", data.Size, []string{"ping", "traceroute"}},
+		{"protocol", data.Protocol, []string{"traceroute", "dns"}},
+		{"paris", data.Paris, []string{"traceroute"}},
+		{"first_hop", data.FirstHop, []string{"traceroute"}},
+		{"max_hops", data.MaxHops, []string{"traceroute"}},
+		{"port", data.Port, []string{"traceroute", "http", "sslcert"}},
+		{"response_timeout", data.ResponseTimeout, []string{"traceroute"}},
+		{"destination_option_size", data.DestinationOptionSize, []string{"traceroute"}},
+		{"hop_by_hop_option_size", data.HopByHopOptionSize, []string{"traceroute"}},
+		{"dont_fragment", data.DontFragment, []string{"traceroute"}},
+		{"query_class", data.QueryClass, []string{"dns"}},
+		{"query_type", data.QueryType, []string{"dns"}},
+		{"query_argument", data.QueryArgument, []string{"dns"}},
+		{"use_probe_resolver", data.UseProbeResolver, []string{"dns"}},
+		{"set_rd_bit", data.SetRDBit, []string{"dns"}},
+		{"set_do_bit", data.SetDOBit, []string{"dns"}},
+		{"set_cd_bit", data.SetCDBit, []string{"dns"}},
+		{"set_nsid_bit", data.SetNSIDBit, []string{"dns"}},
+		{"udp_payload_size", data.UDPPayloadSize, []string{"dns"}},
+		{"retry", data.Retry, []string{"dns"}},
+		{"include_qbuf", data.IncludeQbuf, []string{"dns"}},
+		{"include_abuf", data.IncludeAbuf, []string{"dns"}},
+		{"prepend_probe_id", data.PrependProbeID, []string{"dns"}},
+		{"method", data.Method, []string{"http"}},
+		{"path", data.Path, []string{"http"}},
+		{"query_string", data.QueryString, []string{"http"}},
+		{"header_bytes", data.HeaderBytes, []string{"http"}},
+		{"version", data.Version, []string{"http"}},
+		{"extended_timing", data.ExtendedTiming, []string{"http"}},
+		{"more_extended_timing", data.MoreExtendedTiming, []string{"http"}},
+		{"timeout", data.Timeout, []string{"ntp"}},
+		{"hostname", data.Hostname, []string{"sslcert"}},
+	}
+}
+
+// definition builds the API definition of the planned measurement.
+func (data *MeasurementResourceModel) definition() atlas.Definition {
+	definition := atlas.Definition{
+		Type:           data.Type.ValueString(),
+		Description:    data.Description.ValueString(),
+		AF:             data.AF.ValueInt64(),
+		Target:         data.Target.ValueString(),
+		Interval:       int64Pointer(data.Interval),
+		Spread:         int64Pointer(data.Spread),
+		Tags:           stringList(data.Tags),
+		IsPublic:       boolPointer(data.IsPublic),
+		ResolveOnProbe: boolPointer(data.ResolveOnProbe),
+	}
+
+	switch definition.Type {
+	case "ping":
+		definition.Packets = int64Pointer(data.Packets)
+		definition.Size = int64Pointer(data.Size)
+	case "traceroute":
+		definition.Packets = int64Pointer(data.Packets)
+		definition.Size = int64Pointer(data.Size)
+		definition.Protocol = stringPointer(data.Protocol)
+		definition.Paris = int64Pointer(data.Paris)
+		definition.FirstHop = int64Pointer(data.FirstHop)
+		definition.MaxHops = int64Pointer(data.MaxHops)
+		definition.Port = int64Pointer(data.Port)
+		definition.ResponseTimeout = int64Pointer(data.ResponseTimeout)
+		definition.DestinationOptionSize = int64Pointer(data.DestinationOptionSize)
+		definition.HopByHopOptionSize = int64Pointer(data.HopByHopOptionSize)
+		definition.DontFragment = boolPointer(data.DontFragment)
+	case "dns":
+		definition.Protocol = stringPointer(data.Protocol)
+		definition.QueryClass = stringPointer(data.QueryClass)
+		definition.QueryType = stringPointer(data.QueryType)
+		definition.QueryArgument = stringPointer(data.QueryArgument)
+		definition.UseProbeResolver = boolPointer(data.UseProbeResolver)
+		definition.SetRDBit = boolPointer(data.SetRDBit)
+		definition.SetDOBit = boolPointer(data.SetDOBit)
+		definition.SetCDBit = boolPointer(data.SetCDBit)
+		definition.SetNSIDBit = boolPointer(data.SetNSIDBit)
+		definition.UDPPayloadSize = int64Pointer(data.UDPPayloadSize)
+		definition.Retry = int64Pointer(data.Retry)
+		definition.IncludeQbuf = boolPointer(data.IncludeQbuf)
+		definition.IncludeAbuf = boolPointer(data.IncludeAbuf)
+		definition.PrependProbeID = boolPointer(data.PrependProbeID)
+	case "http":
+		definition.Port = int64Pointer(data.Port)
+		definition.Method = stringPointer(data.Method)
+		definition.Path = stringPointer(data.Path)
+		definition.QueryString = stringPointer(data.QueryString)
+		definition.HeaderBytes = int64Pointer(data.HeaderBytes)
+		definition.Version = stringPointer(data.Version)
+		definition.ExtendedTiming = boolPointer(data.ExtendedTiming)
+		definition.MoreExtendedTiming = boolPointer(data.MoreExtendedTiming)
+	case "ntp":
+		definition.Packets = int64Pointer(data.Packets)
+		definition.Timeout = int64Pointer(data.Timeout)
+	case "sslcert":
+		definition.Port = int64Pointer(data.Port)
+		definition.Hostname = stringPointer(data.Hostname)
+	}
+
+	return definition
+}
+
+// readMeasurement copies the measurement as returned by the API into the model.
+func (data *MeasurementResourceModel) readMeasurement(measurement *atlas.Measurement) {
+	data.ID = types.Int64Value(measurement.ID)
+	data.Description = types.StringValue(measurement.Description)
+	data.Type = types.StringValue(measurement.Type)
+	data.Target = types.StringNull()
+	if measurement.Target != "" {
+		data.Target = types.StringValue(measurement.Target)
+	}
+	data.Interval = types.Int64PointerValue(measurement.Interval)
+	// Scheduling
+	data.IsOneoff = types.BoolValue(measurement.IsOneoff)
+	data.StartTime = timeValue(data.StartTime, measurement.StartTime)
+	data.StopTime = timeValue(data.StopTime, measurement.StopTime)
+	data.Spread = types.Int64PointerValue(measurement.Spread)
+	data.IsPublic = types.BoolPointerValue(measurement.IsPublic)
+	// The API does not tell no tags from an empty list: keep an empty list as configured
+	if len(measurement.Tags) > 0 || len(data.Tags) > 0 {
+		data.Tags = nil
+		for _, tag := range measurement.Tags {
+			data.Tags = append(data.Tags, types.StringValue(tag))
+		}
+	}
+	data.AF = types.Int64Value(measurement.AF)
+	data.ResolveOnProbe = types.BoolPointerValue(measurement.ResolveOnProbe)
+	data.readOptions(measurement)
+}
+
+// int64Pointer returns nil for values that were not configured.
+func int64Pointer(value types.Int64) *int64 {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	return value.ValueInt64Pointer()
+}
+
+// stringPointer returns nil for values that were not configured.
+func stringPointer(value types.String) *string {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	return value.ValueStringPointer()
+}
+
+// parseTime parses an RFC3339 time attribute, returning nil when it is not set.
+func parseTime(value types.String) (*time.Time, error) {
+	if value.IsNull() || value.IsUnknown() {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value.ValueString())
+	if err != nil {
+		return nil, fmt.Errorf("expected an RFC3339 time (e.g. 2006-01-02T15:04:05Z), got: %s", value.ValueString())
+	}
+	return &parsed, nil
+}
+
+// timeValue converts a timestamp into an RFC3339 time, keeping the current
+// value when it represents the same time to avoid needless differences.
+func timeValue(current types.String, timestamp *int64) types.String {
+	if timestamp == nil {
+		return types.StringNull()
+	}
+
+	if parsed, err := parseTime(current); err == nil && parsed != nil && parsed.Unix() == *timestamp {
+		return current
+	}
+	return types.StringValue(time.Unix(*timestamp, 0).UTC().Format(time.RFC3339))
+}
+
+// stringList converts a list attribute into a (never nil) list of strings.
+func stringList(values []types.String) []string {
+	list := []string{}
+	for _, value := range values {
+		list = append(list, value.ValueString())
+	}
+	return list
+}
+
+// boolPointer returns nil for values that were not configured.
+func boolPointer(value types.Bool) *bool {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	return value.ValueBoolPointer()
+}
+
+// readOptions sets the type-specific attributes from a measurement.
+func (data *MeasurementOptionsModel) readOptions(measurement *atlas.Measurement) {
+	// Ping, Traceroute & NTP
+	data.Packets = types.Int64PointerValue(measurement.Packets)
+	// Ping & Traceroute
+	data.Size = types.Int64PointerValue(measurement.Size)
+	// Traceroute & DNS
+	data.Protocol = types.StringPointerValue(measurement.Protocol)
+	// Traceroute, HTTP & SSL Certificate
+	data.Port = types.Int64PointerValue(measurement.Port)
+	// Traceroute
+	data.Paris = types.Int64PointerValue(measurement.Paris)
+	data.FirstHop = types.Int64PointerValue(measurement.FirstHop)
+	data.MaxHops = types.Int64PointerValue(measurement.MaxHops)
+	data.ResponseTimeout = types.Int64PointerValue(measurement.ResponseTimeout)
+	data.DestinationOptionSize = types.Int64PointerValue(measurement.DestinationOptionSize)
+	data.HopByHopOptionSize = types.Int64PointerValue(measurement.HopByHopOptionSize)
+	data.DontFragment = types.BoolPointerValue(measurement.DontFragment)
+	// DNS
+	data.QueryClass = types.StringPointerValue(measurement.QueryClass)
+	data.QueryType = types.StringPointerValue(measurement.QueryType)
+	data.QueryArgument = types.StringPointerValue(measurement.QueryArgument)
+	data.UseProbeResolver = types.BoolPointerValue(measurement.UseProbeResolver)
+	data.SetRDBit = types.BoolPointerValue(measurement.SetRDBit)
+	data.SetDOBit = types.BoolPointerValue(measurement.SetDOBit)
+	data.SetCDBit = types.BoolPointerValue(measurement.SetCDBit)
+	data.SetNSIDBit = types.BoolPointerValue(measurement.SetNSIDBit)
+	data.UDPPayloadSize = types.Int64PointerValue(measurement.UDPPayloadSize)
+	data.Retry = types.Int64PointerValue(measurement.Retry)
+	data.IncludeQbuf = types.BoolPointerValue(measurement.IncludeQbuf)
+	data.IncludeAbuf = types.BoolPointerValue(measurement.IncludeAbuf)
+	data.PrependProbeID = types.BoolPointerValue(measurement.PrependProbeID)
+	// HTTP
+	data.Method = types.StringPointerValue(measurement.Method)
+	data.Path = types.StringPointerValue(measurement.Path)
+	data.QueryString = types.StringPointerValue(measurement.QueryString)
+	data.HeaderBytes = types.Int64PointerValue(measurement.HeaderBytes)
+	data.Version = types.StringPointerValue(measurement.Version)
+	data.ExtendedTiming = types.BoolPointerValue(measurement.ExtendedTiming)
+	data.MoreExtendedTiming = types.BoolPointerValue(measurement.MoreExtendedTiming)
+	// NTP
+	data.Timeout = types.Int64PointerValue(measurement.Timeout)
+	// SSL Certificate
+	data.Hostname = types.StringPointerValue(measurement.Hostname)
 }
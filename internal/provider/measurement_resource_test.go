@@ -4,9 +4,21 @@
 package provider
 
 import (
-	//"fmt"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -17,9 +29,11 @@ func TestAccMeasurementResource(t *testing.T) {
 		Steps: []resource.TestStep{
 			// Create and Read testing
 			{
-				Config: providerConfig + testAccMeasurementResourceConfig(),
+				Config: providerConfig + testAccMeasurementResourceConfig("MyFirstTest"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("ripe-atlas_measurement.test", "description", "MyFirstTest"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.test", "estimated_cost_per_result", "3"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.test", "estimated_cost_per_day", "1080"),
 				),
 			},
 			// ImportState testing
@@ -31,13 +45,14 @@ func TestAccMeasurementResource(t *testing.T) {
 				// example code does not have an actual upstream service.
 				// Once the Read method is able to refresh information from
 				// the upstream service, this can be removed.
-				ImportStateVerifyIgnore: []string{"description", "MyFirstTest"},
+				ImportStateVerifyIgnore: []string{"description", "MyFirstTest", "timeouts"},
 			},
 			// Update and Read testing
 			{
-				Config: providerConfig + testAccMeasurementResourceConfig(),
+				Config: providerConfig + testAccMeasurementResourceConfig("MyFirstTest2"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("ripe-atlas_measurement.test", "description", "MyFirstTest2"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.test", "tags.0", "terraform"),
 				),
 			},
 			// Delete testing automatically occurs in TestCase
@@ -45,11 +60,517 @@ func TestAccMeasurementResource(t *testing.T) {
 	})
 }
 
-func testAccMeasurementResourceConfig() string {
-	return `
+func testAccMeasurementResourceConfig(description string) string {
+	return fmt.Sprintf(`
 	resource "ripe-atlas_measurement" "test" {
-		description = "MyFirstTest"
+		description = %[1]q
 		type        = "ping"
+		target      = "ripe.net"
+		tags        = ["terraform"]
+
+		probe_set = [
+			{
+				type   = "country"
+				value  = "BE"
+				number = 1
+			},
+		]
+
+		timeouts {
+			create = "2m"
+			delete = "1m"
+		}
+	}
+	`, description)
+}
+
+func TestAccMeasurementResourceTraceroute(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccMeasurementResourceTracerouteConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.traceroute", "type", "traceroute"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.traceroute", "protocol", "TCP"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.traceroute", "port", "443"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.traceroute", "max_hops", "16"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "ripe-atlas_measurement.traceroute",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+		},
+	})
+}
+
+func testAccMeasurementResourceTracerouteConfig() string {
+	return `
+	resource "ripe-atlas_measurement" "traceroute" {
+		description = "MyFirstTraceroute"
+		type        = "traceroute"
+		target      = "ripe.net"
+		protocol    = "TCP"
+		port        = 443
+		max_hops    = 16
+
+		probe_set = [
+			{
+				type   = "country"
+				value  = "BE"
+				number = 1
+			},
+		]
 	}
 	`
 }
+
+func TestAccMeasurementResourceDNS(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccMeasurementResourceDNSConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.dns", "type", "dns"),
+					resource.TestCheckNoResourceAttr("ripe-atlas_measurement.dns", "target"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.dns", "query_type", "AAAA"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.dns", "set_do_bit", "true"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "ripe-atlas_measurement.dns",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+		},
+	})
+}
+
+func testAccMeasurementResourceDNSConfig() string {
+	return `
+	resource "ripe-atlas_measurement" "dns" {
+		description        = "MyFirstDNS"
+		type               = "dns"
+		use_probe_resolver = true
+		query_class        = "IN"
+		query_type         = "AAAA"
+		query_argument     = "ripe.net"
+		set_rd_bit         = true
+		set_do_bit         = true
+		protocol           = "UDP"
+
+		probe_set = [
+			{
+				type   = "country"
+				value  = "BE"
+				number = 1
+			},
+		]
+	}
+	`
+}
+
+func TestAccMeasurementResourceHTTP(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccMeasurementResourceHTTPConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.http", "type", "http"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.http", "method", "HEAD"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.http", "path", "/"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.http", "extended_timing", "true"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "ripe-atlas_measurement.http",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+		},
+	})
+}
+
+func testAccMeasurementResourceHTTPConfig() string {
+	return `
+	resource "ripe-atlas_measurement" "http" {
+		description     = "MyFirstHTTP"
+		type            = "http"
+		target          = "nl-ams-as3333.anchors.atlas.ripe.net"
+		method          = "HEAD"
+		path            = "/"
+		port            = 80
+		extended_timing = true
+
+		probe_set = [
+			{
+				type   = "country"
+				value  = "BE"
+				number = 1
+			},
+		]
+	}
+	`
+}
+
+func TestAccMeasurementResourceNTPAndSSLCert(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccMeasurementResourceNTPAndSSLCertConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.ntp", "packets", "3"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.ntp", "timeout", "4000"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.sslcert", "port", "443"),
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.sslcert", "hostname", "www.ripe.net"),
+				),
+			},
+		},
+	})
+}
+
+func testAccMeasurementResourceNTPAndSSLCertConfig() string {
+	return `
+	resource "ripe-atlas_measurement" "ntp" {
+		description = "MyFirstNTP"
+		type        = "ntp"
+		target      = "pool.ntp.org"
+		packets     = 3
+		timeout     = 4000
+
+		probe_set = [
+			{
+				type   = "country"
+				value  = "BE"
+				number = 1
+			},
+		]
+	}
+
+	resource "ripe-atlas_measurement" "sslcert" {
+		description = "MyFirstSSLCert"
+		type        = "sslcert"
+		target      = "www.ripe.net"
+		port        = 443
+		hostname    = "www.ripe.net"
+
+		probe_set = [
+			{
+				type   = "country"
+				value  = "BE"
+				number = 1
+			},
+		]
+	}
+	`
+}
+
+func TestAccMeasurementResourceDualStack(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccMeasurementResourceDualStackConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ripe-atlas_measurement.dual_stack", "dual_stack", "true"),
+					resource.TestCheckResourceAttrSet("ripe-atlas_measurement.dual_stack", "measurement_id_v4"),
+					resource.TestCheckResourceAttrSet("ripe-atlas_measurement.dual_stack", "measurement_id_v6"),
+					resource.TestCheckResourceAttrPair("ripe-atlas_measurement.dual_stack", "id", "ripe-atlas_measurement.dual_stack", "measurement_id_v4"),
+				),
+			},
+		},
+	})
+}
+
+func testAccMeasurementResourceDualStackConfig() string {
+	return `
+	resource "ripe-atlas_measurement" "dual_stack" {
+		description      = "MyFirstDualStack"
+		type             = "ping"
+		target           = "www.ripe.net"
+		dual_stack       = true
+		resolve_on_probe = true
+
+		probe_set = [
+			{
+				type   = "country"
+				value  = "BE"
+				number = 1
+			},
+		]
+	}
+	`
+}
+
+func TestTimeValue(t *testing.T) {
+	timestamp := int64(1767225600) // 2026-01-01T00:00:00Z
+
+	if value := timeValue(types.StringNull(), nil); !value.IsNull() {
+		t.Errorf("expected null, got %s", value)
+	}
+	if value := timeValue(types.StringNull(), &timestamp); value.ValueString() != "2026-01-01T00:00:00Z" {
+		t.Errorf("unexpected time %s", value)
+	}
+	// Same time in another timezone is kept as configured
+	if value := timeValue(types.StringValue("2026-01-01T01:00:00+01:00"), &timestamp); value.ValueString() != "2026-01-01T01:00:00+01:00" {
+		t.Errorf("unexpected time %s", value)
+	}
+	if value := timeValue(types.StringValue("2026-01-02T00:00:00Z"), &timestamp); value.ValueString() != "2026-01-01T00:00:00Z" {
+		t.Errorf("unexpected time %s", value)
+	}
+}
+
+// testMeasurementPlan returns the plan of a measurement for unit tests of the resource.
+func testMeasurementPlan(t *testing.T, data MeasurementResourceModel) tfsdk.Plan {
+	ctx := context.Background()
+	var schemaResp fwresource.SchemaResponse
+	NewMeasurementResource().Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	data.Timeouts = timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+		"create": types.StringType,
+		"read":   types.StringType,
+		"update": types.StringType,
+		"delete": types.StringType,
+	})}
+	plan := tfsdk.Plan{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	if diags := plan.Set(ctx, &data); diags.HasError() {
+		t.Fatalf("unexpected errors %v", diags)
+	}
+	return plan
+}
+
+func TestMeasurementCreateSavesIDs(t *testing.T) {
+	// The measurements are created but cannot be fetched afterwards
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte(`{"measurements": [1001, 1002]}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, err := atlas.New(server.URL, atlas.Keys{}, atlas.Retry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &MeasurementResource{client: client}
+
+	plan := testMeasurementPlan(t, MeasurementResourceModel{
+		Description:       types.StringValue("test"),
+		Type:              types.StringValue("ping"),
+		Target:            types.StringValue("example.com"),
+		IsOneoff:          types.BoolValue(false),
+		DualStack:         types.BoolValue(true),
+		ProbeSet:          []ProbeSetResourceModel{testProbeSet("country", "BE", 1)},
+		RemoveWhenStopped: types.BoolValue(false),
+	})
+	req := fwresource.CreateRequest{Plan: plan}
+	resp := fwresource.CreateResponse{State: tfsdk.State{
+		Schema: plan.Schema,
+		Raw:    tftypes.NewValue(plan.Schema.Type().TerraformType(context.Background()), nil),
+	}}
+	r.Create(context.Background(), req, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error")
+	}
+
+	var state MeasurementResourceModel
+	if diags := resp.State.Get(context.Background(), &state); diags.HasError() {
+		t.Fatalf("unexpected errors %v", diags)
+	}
+	if state.ID.ValueInt64() != 1001 || state.MeasurementIDV4.ValueInt64() != 1001 || state.MeasurementIDV6.ValueInt64() != 1002 || !state.DualStack.ValueBool() {
+		t.Errorf("unexpected state %+v", state)
+	}
+	if ids := state.measurementIDs(); len(ids) != 2 {
+		t.Errorf("expected both measurements to be deleted with the resource, got %v", ids)
+	}
+}
+
+// testMeasurementRead reads the state of a measurement from a server answering the measurements by path.
+func testMeasurementRead(t *testing.T, data MeasurementResourceModel, measurements map[string]string) fwresource.ReadResponse {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		measurement, ok := measurements[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(measurement))
+	}))
+	defer server.Close()
+
+	client, err := atlas.New(server.URL, atlas.Keys{}, atlas.Retry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &MeasurementResource{client: client}
+
+	plan := testMeasurementPlan(t, data)
+	state := tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}
+	resp := fwresource.ReadResponse{State: state}
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors %v", resp.Diagnostics)
+	}
+	return resp
+}
+
+func TestMeasurementReadDualStack(t *testing.T) {
+	data := MeasurementResourceModel{
+		ID:              types.Int64Value(1001),
+		Description:     types.StringValue("test"),
+		Type:            types.StringValue("ping"),
+		DualStack:       types.BoolValue(true),
+		MeasurementIDV4: types.Int64Value(1001),
+		MeasurementIDV6: types.Int64Value(1002),
+	}
+	measurementV4 := `{"id": 1001, "type": "ping", "description": "test", "af": 4, "status": {"id": 2, "name": "Ongoing"}}`
+	measurementV6 := `{"id": 1002, "type": "ping", "description": "test", "af": 6, "status": {"id": 2, "name": "Ongoing"}}`
+
+	readState := func(measurements map[string]string) (MeasurementResourceModel, bool) {
+		resp := testMeasurementRead(t, data, measurements)
+		if resp.State.Raw.IsNull() {
+			return MeasurementResourceModel{}, false
+		}
+		var state MeasurementResourceModel
+		if diags := resp.State.Get(context.Background(), &state); diags.HasError() {
+			t.Fatalf("unexpected errors %v", diags)
+		}
+		return state, true
+	}
+
+	// Both measurements exist
+	state, ok := readState(map[string]string{
+		"/measurements/1001/": measurementV4,
+		"/measurements/1002/": measurementV6,
+	})
+	if !ok || state.isDualStackIncomplete() {
+		t.Errorf("expected both measurements to be kept, got %+v", state)
+	}
+
+	// The IPv6 measurement was deleted: the IPv4 one is kept to be stopped on replacement
+	state, ok = readState(map[string]string{
+		"/measurements/1001/": measurementV4,
+	})
+	if !ok || state.ID.ValueInt64() != 1001 || state.MeasurementIDV4.ValueInt64() != 1001 || !state.MeasurementIDV6.IsNull() {
+		t.Errorf("expected the IPv4 measurement to be kept, got %+v", state)
+	}
+	if ids := state.measurementIDs(); len(ids) != 1 || ids[0] != 1001 {
+		t.Errorf("unexpected measurement IDs %v", ids)
+	}
+
+	// The IPv4 measurement was deleted
+	state, ok = readState(map[string]string{
+		"/measurements/1002/": measurementV6,
+	})
+	if !ok || state.ID.ValueInt64() != 1002 || !state.MeasurementIDV4.IsNull() || state.MeasurementIDV6.ValueInt64() != 1002 {
+		t.Errorf("expected the IPv6 measurement to be kept, got %+v", state)
+	}
+
+	// Both measurements were deleted
+	if _, ok = readState(map[string]string{}); ok {
+		t.Error("expected the measurement to be removed")
+	}
+}
+
+func TestModifyPlanIncompleteDualStack(t *testing.T) {
+	ctx := context.Background()
+	data := MeasurementResourceModel{
+		ID:              types.Int64Value(1001),
+		Description:     types.StringValue("test"),
+		Type:            types.StringValue("ping"),
+		Target:          types.StringValue("example.com"),
+		IsOneoff:        types.BoolValue(false),
+		DualStack:       types.BoolValue(true),
+		MeasurementIDV4: types.Int64Value(1001),
+		MeasurementIDV6: types.Int64Null(),
+		ProbeSet:        []ProbeSetResourceModel{testProbeSet("country", "BE", 1)},
+	}
+	plan := testMeasurementPlan(t, data)
+
+	req := fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   plan,
+		State:  tfsdk.State{Schema: plan.Schema, Raw: plan.Raw},
+	}
+	resp := fwresource.ModifyPlanResponse{Plan: plan}
+	(&MeasurementResource{}).ModifyPlan(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors %v", resp.Diagnostics)
+	}
+	if !resp.RequiresReplace.Contains(path.Root("measurement_id_v6")) {
+		t.Errorf("expected the measurement to be replaced, got %v", resp.RequiresReplace)
+	}
+}
+
+func TestMeasurementReadRemoveWhenStopped(t *testing.T) {
+	data := MeasurementResourceModel{
+		ID:                types.Int64Value(1001),
+		Description:       types.StringValue("test"),
+		Type:              types.StringValue("ping"),
+		StopTime:          types.StringValue("2026-01-01T00:00:00Z"),
+		RemoveWhenStopped: types.BoolValue(true),
+	}
+	stopped := `{"id": 1001, "type": "ping", "description": "test", "af": 4, "stop_time": %d, "status": {"id": 4, "name": "Stopped"}}`
+
+	// Stopped at its stop_time
+	resp := testMeasurementRead(t, data, map[string]string{
+		"/measurements/1001/": fmt.Sprintf(stopped, 1767225600),
+	})
+	if resp.State.Raw.IsNull() {
+		t.Error("expected the measurement stopped at its stop_time to be kept")
+	}
+
+	// Stopped before its stop_time
+	resp = testMeasurementRead(t, data, map[string]string{
+		"/measurements/1001/": fmt.Sprintf(stopped, 1767139200),
+	})
+	if !resp.State.Raw.IsNull() {
+		t.Error("expected the measurement stopped before its stop_time to be removed")
+	}
+}
+
+func TestReadMeasurementEmptyTags(t *testing.T) {
+	measurement := &atlas.Measurement{}
+
+	// Cleared tags stay an empty list
+	data := MeasurementResourceModel{Tags: []types.String{}}
+	data.readMeasurement(measurement)
+	if data.Tags == nil {
+		t.Error("expected an empty list of tags")
+	}
+
+	// Tags removed outside of Terraform
+	data = MeasurementResourceModel{Tags: []types.String{types.StringValue("old")}}
+	data.readMeasurement(measurement)
+	if data.Tags != nil {
+		t.Errorf("expected no tags, got %v", data.Tags)
+	}
+
+	// Tags added outside of Terraform
+	data = MeasurementResourceModel{}
+	measurement.Tags = []string{"new"}
+	data.readMeasurement(measurement)
+	if len(data.Tags) != 1 || data.Tags[0].ValueString() != "new" {
+		t.Errorf("unexpected tags %v", data.Tags)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ProbeDataSource{}
+var _ datasource.DataSourceWithConfigure = &ProbeDataSource{}
+
+func NewProbeDataSource() datasource.DataSource {
+	return &ProbeDataSource{}
+}
+
+// ProbeDataSource defines the data source implementation.
+type ProbeDataSource struct {
+	client *atlas.Client
+}
+
+// ProbeModel describes a probe, for the probe and probes data sources.
+type ProbeModel struct {
+	ID          types.Int64    `tfsdk:"id"`
+	Description types.String   `tfsdk:"description"`
+	AddressV4   types.String   `tfsdk:"address_v4"`
+	AddressV6   types.String   `tfsdk:"address_v6"`
+	PrefixV4    types.String   `tfsdk:"prefix_v4"`
+	PrefixV6    types.String   `tfsdk:"prefix_v6"`
+	ASNV4       types.Int64    `tfsdk:"asn_v4"`
+	ASNV6       types.Int64    `tfsdk:"asn_v6"`
+	CountryCode types.String   `tfsdk:"country_code"`
+	Latitude    types.Float64  `tfsdk:"latitude"`
+	Longitude   types.Float64  `tfsdk:"longitude"`
+	Status      types.String   `tfsdk:"status"`
+	StatusSince types.String   `tfsdk:"status_since"`
+	IsAnchor    types.Bool     `tfsdk:"is_anchor"`
+	IsPublic    types.Bool     `tfsdk:"is_public"`
+	Tags        []types.String `tfsdk:"tags"`
+}
+
+// probeAttributes are the computed attributes of a probe (id excepted).
+func probeAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"description": schema.StringAttribute{
+			Computed: true,
+		},
+		"address_v4": schema.StringAttribute{
+			Computed: true,
+		},
+		"address_v6": schema.StringAttribute{
+			Computed: true,
+		},
+		"prefix_v4": schema.StringAttribute{
+			Computed: true,
+		},
+		"prefix_v6": schema.StringAttribute{
+			Computed: true,
+		},
+		"asn_v4": schema.Int64Attribute{
+			Computed: true,
+		},
+		"asn_v6": schema.Int64Attribute{
+			Computed: true,
+		},
+		"country_code": schema.StringAttribute{
+			Computed: true,
+		},
+		"latitude": schema.Float64Attribute{
+			Computed: true,
+		},
+		"longitude": schema.Float64Attribute{
+			Computed: true,
+		},
+		"status": schema.StringAttribute{
+			MarkdownDescription: "Connection status (Connected, Disconnected, Abandoned, Never Connected)",
+			Computed:            true,
+		},
+		"status_since": schema.StringAttribute{
+			MarkdownDescription: "When the probe got its status (RFC3339)",
+			Computed:            true,
+		},
+		"is_anchor": schema.BoolAttribute{
+			Computed: true,
+		},
+		"is_public": schema.BoolAttribute{
+			Computed: true,
+		},
+		"tags": schema.ListAttribute{
+			MarkdownDescription: "System (system-*) and user tags of the probe",
+			ElementType:         types.StringType,
+			Computed:            true,
+		},
+	}
+}
+
+// probeModel converts a probe from the API.
+func probeModel(probe atlas.Probe) ProbeModel {
+	m := ProbeModel{
+		ID:          types.Int64Value(probe.ID),
+		Description: types.StringValue(probe.Description),
+		AddressV4:   optionalString(probe.AddressV4),
+		AddressV6:   optionalString(probe.AddressV6),
+		PrefixV4:    optionalString(probe.PrefixV4),
+		PrefixV6:    optionalString(probe.PrefixV6),
+		ASNV4:       optionalInt64(probe.ASNV4),
+		ASNV6:       optionalInt64(probe.ASNV6),
+		CountryCode: types.StringValue(probe.CountryCode),
+		Latitude:    types.Float64Null(),
+		Longitude:   types.Float64Null(),
+		Status:      types.StringValue(probe.Status.Name),
+		StatusSince: optionalString(probe.Status.Since),
+		IsAnchor:    types.BoolValue(probe.IsAnchor),
+		IsPublic:    types.BoolValue(probe.IsPublic),
+		Tags:        []types.String{},
+	}
+	if probe.Geometry != nil && len(probe.Geometry.Coordinates) == 2 {
+		m.Longitude = types.Float64Value(probe.Geometry.Coordinates[0])
+		m.Latitude = types.Float64Value(probe.Geometry.Coordinates[1])
+	}
+	for _, tag := range probe.Tags {
+		m.Tags = append(m.Tags, types.StringValue(tag.Slug))
+	}
+	return m
+}
+
+// optionalString returns null for empty strings.
+func optionalString(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// optionalInt64 returns null for 0 (e.g. missing ASNs).
+func optionalInt64(value int64) types.Int64 {
+	if value == 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(value)
+}
+
+func (d *ProbeDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_probe"
+}
+
+func (d *ProbeDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := probeAttributes()
+	attributes["id"] = schema.Int64Attribute{
+		MarkdownDescription: "Probe ID",
+		Required:            true,
+	}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "RIPE Atlas Probe",
+
+		Attributes: attributes,
+	}
+}
+
+func (d *ProbeDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*atlas.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *atlas.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ProbeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Read Terraform data into the model
+	var data ProbeModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Fetch data from API
+	probe, err := d.client.GetProbe(ctx, data.ID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get probe from RIPE Atlas",
+			fmt.Sprintf("Unable to get probe %d, got error: %s", data.ID.ValueInt64(), err),
+		)
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "probe", probe)
+	tflog.Info(ctx, "RIPE Atlas probe")
+
+	data = probeModel(*probe)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
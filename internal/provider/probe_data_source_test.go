@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccProbeDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + testAccProbeDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ripe-atlas_probe.test", "id", "6001"),
+					resource.TestCheckResourceAttr("data.ripe-atlas_probe.test", "is_anchor", "true"),
+					resource.TestCheckResourceAttrSet("data.ripe-atlas_probe.test", "country_code"),
+					resource.TestCheckResourceAttrSet("data.ripe-atlas_probe.test", "status"),
+				),
+			},
+		},
+	})
+}
+
+const testAccProbeDataSourceConfig = `
+data "ripe-atlas_probe" "test" {
+	id = 6001
+}
+`
+
+func TestProbeModel(t *testing.T) {
+	probe := atlas.Probe{
+		ID:          1234,
+		CountryCode: "BE",
+		ASNV4:       3333,
+		AddressV4:   "192.0.2.10",
+		PrefixV4:    "192.0.2.0/24",
+		Geometry:    &atlas.Geometry{Type: "Point", Coordinates: []float64{4.35, 50.85}},
+		Tags:        []atlas.Tag{{Name: "IPv4 Works", Slug: "system-ipv4-works"}},
+	}
+	probe.Status.Name = "Connected"
+	probe.Status.Since = "2024-01-01T00:00:00Z"
+
+	m := probeModel(probe)
+	if m.ASNV4.ValueInt64() != 3333 || !m.ASNV6.IsNull() || !m.AddressV6.IsNull() {
+		t.Errorf("unexpected addressing %+v", m)
+	}
+	if m.Latitude.ValueFloat64() != 50.85 || m.Longitude.ValueFloat64() != 4.35 {
+		t.Errorf("unexpected location %s, %s", m.Latitude, m.Longitude)
+	}
+	if m.Status.ValueString() != "Connected" || m.StatusSince.ValueString() != "2024-01-01T00:00:00Z" {
+		t.Errorf("unexpected status %s since %s", m.Status, m.StatusSince)
+	}
+	if len(m.Tags) != 1 || m.Tags[0].ValueString() != "system-ipv4-works" {
+		t.Errorf("unexpected tags %v", m.Tags)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"terraform-provider-ripe-atlas/internal/atlas"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ProbesDataSource{}
+var _ datasource.DataSourceWithConfigure = &ProbesDataSource{}
+
+func NewProbesDataSource() datasource.DataSource {
+	return &ProbesDataSource{}
+}
+
+// ProbesDataSource defines the data source implementation.
+type ProbesDataSource struct {
+	client *atlas.Client
+}
+
+// ProbesDataSourceModel describes the data source data model.
+type ProbesDataSourceModel struct {
+	// Filters
+	CountryCode types.String      `tfsdk:"country_code"`
+	ASNV4       types.Int64       `tfsdk:"asn_v4"`
+	ASNV6       types.Int64       `tfsdk:"asn_v6"`
+	PrefixV4    types.String      `tfsdk:"prefix_v4"`
+	PrefixV6    types.String      `tfsdk:"prefix_v6"`
+	Status      types.String      `tfsdk:"status"`
+	IsAnchor    types.Bool        `tfsdk:"is_anchor"`
+	Tags        []types.String    `tfsdk:"tags"`
+	Radius      *ProbeRadiusModel `tfsdk:"radius"`
+	// Results
+	IDs    []types.Int64 `tfsdk:"ids"`
+	Probes []ProbeModel  `tfsdk:"probes"`
+}
+
+// ProbeRadiusModel selects the probes around a location.
+type ProbeRadiusModel struct {
+	Latitude   types.Float64 `tfsdk:"latitude"`
+	Longitude  types.Float64 `tfsdk:"longitude"`
+	DistanceKm types.Float64 `tfsdk:"distance_km"`
+}
+
+// probeStatuses are the probe status IDs by name.
+var probeStatuses = map[string]string{
+	"Never Connected": "0",
+	"Connected":       "1",
+	"Disconnected":    "2",
+	"Abandoned":       "3",
+}
+
+func (d *ProbesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_probes"
+}
+
+func (d *ProbesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	probe := probeAttributes()
+	probe["id"] = schema.Int64Attribute{
+		Computed: true,
+	}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "RIPE Atlas Probes matching all the given filters",
+
+		Attributes: map[string]schema.Attribute{
+			"country_code": schema.StringAttribute{
+				MarkdownDescription: "ISO 3166-1 alpha-2 country code",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(2, 2),
+				},
+			},
+			"asn_v4": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"asn_v6": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"prefix_v4": schema.StringAttribute{
+				MarkdownDescription: "IPv4 prefix the probe address is part of",
+				Optional:            true,
+			},
+			"prefix_v6": schema.StringAttribute{
+				MarkdownDescription: "IPv6 prefix the probe address is part of",
+				Optional:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Connection status (Connected, Disconnected, Abandoned, Never Connected)",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("Never Connected", "Connected", "Disconnected", "Abandoned"),
+				},
+			},
+			"is_anchor": schema.BoolAttribute{
+				Optional: true,
+			},
+			"tags": schema.ListAttribute{
+				MarkdownDescription: "Only probes having all these tags",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"radius": schema.SingleNestedAttribute{
+				MarkdownDescription: "Only probes within a distance of a location",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"latitude": schema.Float64Attribute{
+						Required: true,
+						Validators: []validator.Float64{
+							float64validator.Between(-90, 90),
+						},
+					},
+					"longitude": schema.Float64Attribute{
+						Required: true,
+						Validators: []validator.Float64{
+							float64validator.Between(-180, 180),
+						},
+					},
+					"distance_km": schema.Float64Attribute{
+						Required: true,
+						Validators: []validator.Float64{
+							float64validator.AtLeast(0),
+						},
+					},
+				},
+			},
+			"ids": schema.ListAttribute{
+				MarkdownDescription: "IDs of the probes, e.g. for a probe_set of type probes",
+				ElementType:         types.Int64Type,
+				Computed:            true,
+			},
+			"probes": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: probe,
+				},
+			},
+		},
+	}
+}
+
+func (d *ProbesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*atlas.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *atlas.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// filters converts the configured filters into query parameters of /probes/.
+func (data *ProbesDataSourceModel) filters() url.Values {
+	filters := url.Values{}
+	filters.Set("page_size", "500")
+	if !data.CountryCode.IsNull() {
+		filters.Set("country_code", strings.ToUpper(data.CountryCode.ValueString()))
+	}
+	if !data.ASNV4.IsNull() {
+		filters.Set("asn_v4", strconv.FormatInt(data.ASNV4.ValueInt64(), 10))
+	}
+	if !data.ASNV6.IsNull() {
+		filters.Set("asn_v6", strconv.FormatInt(data.ASNV6.ValueInt64(), 10))
+	}
+	if !data.PrefixV4.IsNull() {
+		filters.Set("prefix_v4", data.PrefixV4.ValueString())
+	}
+	if !data.PrefixV6.IsNull() {
+		filters.Set("prefix_v6", data.PrefixV6.ValueString())
+	}
+	if !data.Status.IsNull() {
+		filters.Set("status", probeStatuses[data.Status.ValueString()])
+	}
+	if !data.IsAnchor.IsNull() {
+		filters.Set("is_anchor", strconv.FormatBool(data.IsAnchor.ValueBool()))
+	}
+	if len(data.Tags) > 0 {
+		filters.Set("tags", strings.Join(stringList(data.Tags), ","))
+	}
+	if data.Radius != nil {
+		filters.Set("radius", fmt.Sprintf("%s,%s:%s",
+			strconv.FormatFloat(data.Radius.Latitude.ValueFloat64(), 'f', -1, 64),
+			strconv.FormatFloat(data.Radius.Longitude.ValueFloat64(), 'f', -1, 64),
+			strconv.FormatFloat(data.Radius.DistanceKm.ValueFloat64(), 'f', -1, 64),
+		))
+	}
+	return filters
+}
+
+func (d *ProbesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Read Terraform data into the model
+	var data ProbesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Fetch data from API
+	filters := data.filters()
+	ctx = tflog.SetField(ctx, "filters", filters.Encode())
+	probes, err := d.client.ListProbes(ctx, filters)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get probes from RIPE Atlas",
+			err.Error(),
+		)
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "count", len(probes))
+	tflog.Info(ctx, "RIPE Atlas probes")
+
+	data.IDs = []types.Int64{}
+	data.Probes = []ProbeModel{}
+	for _, probe := range probes {
+		data.IDs = append(data.IDs, types.Int64Value(probe.ID))
+		data.Probes = append(data.Probes, probeModel(probe))
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
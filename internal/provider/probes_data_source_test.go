@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccProbesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + testAccProbesDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.ripe-atlas_probes.test", "ids.0"),
+					resource.TestCheckResourceAttr("data.ripe-atlas_probes.test", "probes.0.country_code", "BE"),
+					resource.TestCheckResourceAttr("data.ripe-atlas_probes.test", "probes.0.status", "Connected"),
+				),
+			},
+		},
+	})
+}
+
+const testAccProbesDataSourceConfig = `
+data "ripe-atlas_probes" "test" {
+	country_code = "BE"
+	status       = "Connected"
+	tags         = ["system-ipv6-works"]
+}
+`
+
+func TestProbesFilters(t *testing.T) {
+	data := ProbesDataSourceModel{
+		CountryCode: types.StringValue("be"),
+		ASNV4:       types.Int64Value(3333),
+		ASNV6:       types.Int64Null(),
+		PrefixV4:    types.StringNull(),
+		PrefixV6:    types.StringValue("2001:db8::/32"),
+		Status:      types.StringValue("Connected"),
+		IsAnchor:    types.BoolValue(false),
+		Tags:        []types.String{types.StringValue("system-ipv4-works"), types.StringValue("home")},
+		Radius: &ProbeRadiusModel{
+			Latitude:   types.Float64Value(50.85),
+			Longitude:  types.Float64Value(4.35),
+			DistanceKm: types.Float64Value(25),
+		},
+	}
+
+	expected := "asn_v4=3333&country_code=BE&is_anchor=false&page_size=500&prefix_v6=2001%3Adb8%3A%3A%2F32&radius=50.85%2C4.35%3A25&status=1&tags=system-ipv4-works%2Chome"
+	if actual := data.filters().Encode(); actual != expected {
+		t.Errorf("unexpected filters %s", actual)
+	}
+}
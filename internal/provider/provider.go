@@ -4,18 +4,29 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
 	//"net/http"
 
-	"github.com/keltia/ripe-atlas" // PR https://github.com/keltia/ripe-atlas/pull/13
+	"terraform-provider-ripe-atlas/internal/atlas"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	//"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -23,6 +34,7 @@ import (
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
 var _ provider.Provider = &RipeAtlasProvider{}
 var _ provider.ProviderWithFunctions = &RipeAtlasProvider{}
+var _ provider.ProviderWithConfigValidators = &RipeAtlasProvider{}
 
 // ripeAtlasProvider defines the provider implementation.
 type RipeAtlasProvider struct {
@@ -34,7 +46,20 @@ type RipeAtlasProvider struct {
 
 // ScaffoldingProviderModel describes the provider data model.
 type RipeAtlasProviderModel struct {
-	ApiKey types.String `tfsdk:"api_key"`
+	ApiKey        types.String   `tfsdk:"api_key"`
+	ApiKeyFile    types.String   `tfsdk:"api_key_file"`
+	ApiKeyCommand []types.String `tfsdk:"api_key_command"`
+	CreateKey     types.String   `tfsdk:"create_key"`
+	DeleteKey     types.String   `tfsdk:"delete_key"`
+	ReadKey       types.String   `tfsdk:"read_key"`
+	CreditsKey    types.String   `tfsdk:"credits_key"`
+	Endpoint      types.String   `tfsdk:"endpoint"`
+	MaxRetries    types.Int64    `tfsdk:"max_retries"`
+	RetryMinWait  types.Int64    `tfsdk:"retry_min_wait"`
+	RetryMaxWait  types.Int64    `tfsdk:"retry_max_wait"`
+	MaxDailySpend types.Int64    `tfsdk:"max_daily_spend"`
+	MinRunoutDays types.Int64    `tfsdk:"min_runout_days"`
+	BudgetAction  types.String   `tfsdk:"budget_action"`
 }
 
 func (p *RipeAtlasProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -46,14 +71,102 @@ func (p *RipeAtlasProvider) Schema(ctx context.Context, req provider.SchemaReque
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"api_key": schema.StringAttribute{
-				MarkdownDescription: "RIPE Atlas API Key",
-				Required:            true,
+				MarkdownDescription: "RIPE Atlas API Key, used for every operation without a more specific key. Can also be set with the RIPE_ATLAS_API_KEY environment variable.",
+				Optional:            true,
 				Sensitive:           true,
 			},
+			"api_key_file": schema.StringAttribute{
+				MarkdownDescription: "Path of a file containing the RIPE Atlas API Key, instead of api_key.",
+				Optional:            true,
+			},
+			"api_key_command": schema.ListAttribute{
+				MarkdownDescription: "Command (program and arguments) printing the RIPE Atlas API Key on its standard output, instead of api_key. E.g. `[\"pass\", \"show\", \"ripe-atlas\"]`.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"create_key": schema.StringAttribute{
+				MarkdownDescription: "RIPE Atlas API Key to create measurements, update them and change their probes (defaults to api_key). Can also be set with the RIPE_ATLAS_CREATE_KEY environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"delete_key": schema.StringAttribute{
+				MarkdownDescription: "RIPE Atlas API Key to stop measurements (defaults to api_key). Can also be set with the RIPE_ATLAS_DELETE_KEY environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"read_key": schema.StringAttribute{
+				MarkdownDescription: "RIPE Atlas API Key to read measurements and probes (defaults to api_key). Can also be set with the RIPE_ATLAS_READ_KEY environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"credits_key": schema.StringAttribute{
+				MarkdownDescription: "RIPE Atlas API Key to read credits (defaults to api_key). Can also be set with the RIPE_ATLAS_CREDITS_KEY environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "RIPE Atlas API base URL (defaults to " + atlas.DefaultEndpoint + "). Can also be set with the RIPE_ATLAS_ENDPOINT environment variable.",
+				Optional:            true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of retries of an API request that was rate limited or failed temporarily (defaults to %d). Requests creating or changing measurements are only retried when the API refused them, never after a gateway or network failure.", atlas.DefaultRetry.MaxRetries),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_min_wait": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Seconds to wait before the first retry, doubled on every next retry (defaults to %d). A Retry-After sent by the API takes precedence.", int64(atlas.DefaultRetry.MinWait.Seconds())),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_max_wait": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of seconds to wait between retries (defaults to %d).", int64(atlas.DefaultRetry.MaxWait.Seconds())),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"max_daily_spend": schema.Int64Attribute{
+				MarkdownDescription: "Maximum estimated daily spend of the account in credits, once the planned measurements are applied. Only the measurements increasing the spend are counted, the savings of removed or smaller measurements are not deducted.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"min_runout_days": schema.Int64Attribute{
+				MarkdownDescription: "Minimum number of days before the credits balance runs out, once the planned measurements are applied. Only the measurements increasing the spend are counted, the savings of removed or smaller measurements are not deducted.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"budget_action": schema.StringAttribute{
+				MarkdownDescription: "What to do when max_daily_spend or min_runout_days would be exceeded: `error` (default) fails the plan, `warn` only warns.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("error", "warn"),
+				},
+			},
 		},
 	}
 }
 
+func (p *RipeAtlasProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.Conflicting(
+			path.MatchRoot("api_key"),
+			path.MatchRoot("api_key_file"),
+			path.MatchRoot("api_key_command"),
+		),
+	}
+}
+
 func (p *RipeAtlasProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data RipeAtlasProviderModel
 
@@ -73,6 +186,39 @@ func (p *RipeAtlasProvider) Configure(ctx context.Context, req provider.Configur
 		)
 	}
 
+	if data.ApiKeyFile.IsUnknown() || slices.ContainsFunc(data.ApiKeyCommand, types.String.IsUnknown) {
+		resp.Diagnostics.AddError(
+			"Unknown RIPE Atlas API Key Source",
+			"The provider cannot create the RIPE Atlas API client as api_key_file or api_key_command depends on an unknown value. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	for attribute, value := range map[string]types.String{
+		"create_key":  data.CreateKey,
+		"delete_key":  data.DeleteKey,
+		"read_key":    data.ReadKey,
+		"credits_key": data.CreditsKey,
+	} {
+		if value.IsUnknown() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Unknown RIPE Atlas API Key",
+				"The provider cannot create the RIPE Atlas API client as there is an unknown configuration value for "+attribute+". "+
+					"Either target apply the source of the value first, set the value statically in the configuration, or use the RIPE_ATLAS_"+strings.ToUpper(attribute)+" environment variable.",
+			)
+		}
+	}
+
+	if data.Endpoint.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Unknown RIPE Atlas API Endpoint",
+			"The provider cannot create the RIPE Atlas API client as there is an unknown configuration value for the RIPE Atlas API Endpoint. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the RIPE_ATLAS_ENDPOINT environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -83,24 +229,82 @@ func (p *RipeAtlasProvider) Configure(ctx context.Context, req provider.Configur
 	if !data.ApiKey.IsNull() {
 		api_key = data.ApiKey.ValueString()
 	}
-	if api_key == "" {
+	if !data.ApiKeyFile.IsNull() {
+		key, err := apiKeyFromFile(data.ApiKeyFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_key_file"),
+				"Unable to Read RIPE Atlas API Key File",
+				"RIPE Atlas API Key File Error: "+err.Error(),
+			)
+			return
+		}
+		api_key = key
+	}
+	if data.ApiKeyCommand != nil {
+		key, err := apiKeyFromCommand(ctx, stringList(data.ApiKeyCommand))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_key_command"),
+				"Unable to Run RIPE Atlas API Key Command",
+				"RIPE Atlas API Key Command Error: "+err.Error(),
+			)
+			return
+		}
+		api_key = key
+	}
+
+	// Per-permission keys fall back to api_key in the client
+	keys := atlas.Keys{
+		Default: api_key,
+		Create:  configValue(data.CreateKey, "RIPE_ATLAS_CREATE_KEY"),
+		Delete:  configValue(data.DeleteKey, "RIPE_ATLAS_DELETE_KEY"),
+		Read:    configValue(data.ReadKey, "RIPE_ATLAS_READ_KEY"),
+		Credits: configValue(data.CreditsKey, "RIPE_ATLAS_CREDITS_KEY"),
+	}
+
+	endpoint := os.Getenv("RIPE_ATLAS_ENDPOINT")
+	if !data.Endpoint.IsNull() {
+		endpoint = data.Endpoint.ValueString()
+	}
+	if endpoint == "" {
+		endpoint = atlas.DefaultEndpoint
+	}
+
+	if keys == (atlas.Keys{}) {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("api_key"),
 			"Missing RIPE Atlas API Key",
 			"The provider cannot create the  RIPE Atlas API client as there is a missing or empty value for the  RIPE Atlas API Key. "+
-				"Set the host value in the configuration or use the RIPE_ATLAS_API_KEY environment variable. "+
+				"Set api_key (or one of create_key, delete_key, read_key and credits_key) in the configuration or use the RIPE_ATLAS_API_KEY environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
+	retry := atlas.DefaultRetry
+	if !data.MaxRetries.IsNull() {
+		retry.MaxRetries = data.MaxRetries.ValueInt64()
+	}
+	if !data.RetryMinWait.IsNull() {
+		retry.MinWait = time.Duration(data.RetryMinWait.ValueInt64()) * time.Second
+	}
+	if !data.RetryMaxWait.IsNull() {
+		retry.MaxWait = time.Duration(data.RetryMaxWait.ValueInt64()) * time.Second
+	}
+	if retry.MinWait > retry.MaxWait {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retry_min_wait"),
+			"Invalid RIPE Atlas Retry Configuration",
+			"retry_min_wait should not be greater than retry_max_wait.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Create a new HashiCups client using the configuration values
-	client, err := atlas.NewClient(atlas.Config{
-		APIKey: api_key,
-	})
+	// Create a new RIPE Atlas client using the configuration values
+	client, err := atlas.New(endpoint, keys, retry)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create RIPE Atlas API Client",
@@ -111,8 +315,51 @@ func (p *RipeAtlasProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
+	budget := &creditBudget{
+		client:        client,
+		maxDailySpend: data.MaxDailySpend.ValueInt64Pointer(),
+		minRunoutDays: data.MinRunoutDays.ValueInt64Pointer(),
+		warn:          data.BudgetAction.ValueString() == "warn",
+	}
+
 	resp.DataSourceData = client
-	resp.ResourceData = client
+	resp.ResourceData = &resourceData{
+		client: client,
+		budget: budget,
+	}
+}
+
+// apiKeyFromFile reads an API key from a file.
+func apiKeyFromFile(name string) (string, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// apiKeyFromCommand runs a command and returns its output as API key.
+func apiKeyFromCommand(ctx context.Context, command []string) (string, error) {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if stderr.Len() > 0 {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// configValue returns the configured value, or the environment variable when not configured.
+func configValue(value types.String, environment string) string {
+	if !value.IsNull() {
+		return value.ValueString()
+	}
+	return os.Getenv(environment)
 }
 
 func (p *RipeAtlasProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -124,7 +371,11 @@ func (p *RipeAtlasProvider) Resources(ctx context.Context) []func() resource.Res
 func (p *RipeAtlasProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewMeasurementDataSource,
+		NewMeasurementInfoDataSource,
 		NewCreditsDataSource,
+		NewProbeDataSource,
+		NewProbesDataSource,
+		NewAnchorsDataSource,
 	}
 }
 
@@ -4,10 +4,17 @@
 package provider
 
 import (
-	//"testing"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 const (
@@ -25,3 +32,73 @@ provider "ripe-atlas" {
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
 	"ripe-atlas": providerserver.NewProtocol6WithError(New("test")()),
 }
+
+func TestApiKeyFromFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(name, []byte("KEY-FROM-FILE\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := apiKeyFromFile(name)
+	if err != nil || key != "KEY-FROM-FILE" {
+		t.Errorf("unexpected key %q (%v)", key, err)
+	}
+
+	if _, err := apiKeyFromFile(name + ".missing"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestApiKeyFromCommand(t *testing.T) {
+	key, err := apiKeyFromCommand(context.Background(), []string{"echo", "KEY-FROM-COMMAND"})
+	if err != nil || key != "KEY-FROM-COMMAND" {
+		t.Errorf("unexpected key %q (%v)", key, err)
+	}
+
+	if _, err := apiKeyFromCommand(context.Background(), []string{"false"}); err == nil {
+		t.Error("expected an error for a failing command")
+	}
+}
+
+func TestConfigureApiKeyErrors(t *testing.T) {
+	ctx := context.Background()
+	p := New("test")()
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	cases := map[string]RipeAtlasProviderModel{
+		"api_key_file":    {ApiKeyFile: types.StringValue(filepath.Join(t.TempDir(), "missing"))},
+		"api_key_command": {ApiKeyCommand: []types.String{types.StringValue("false")}},
+	}
+	for name, data := range cases {
+		state := tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		}
+		if diags := state.Set(ctx, &data); diags.HasError() {
+			t.Fatalf("unexpected errors %v", diags)
+		}
+
+		// Only the error of the key source is reported, not a missing key
+		var resp provider.ConfigureResponse
+		p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}, &resp)
+		if resp.Diagnostics.ErrorsCount() != 1 {
+			t.Errorf("%s: expected a single error, got %v", name, resp.Diagnostics)
+		}
+	}
+}
+
+func TestProviderSchema(t *testing.T) {
+	server, err := testAccProtoV6ProviderFactories["ripe-atlas"]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("%s: %s", d.Summary, d.Detail)
+	}
+}